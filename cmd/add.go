@@ -51,18 +51,28 @@ func (a *addCommand) add(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("could not parse priority value %s: %w", a.prio, err)
 	}
+	now := cmd.Context().Value(cmdutil.DiKey).(*di.Container).Config().NowFunc
+	newItem, err := addTask(a.def, list, description, prio, now)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added task #%d\n", list.LineOf(newItem))
+	return nil
+}
+
+// addTask adds a new task to list, which is surrounded by the prefix and suffix of the view
+func addTask(def di.ViewDef, list *todotxt.List, description string, prio todotxt.Priority, now func() time.Time) (*todotxt.Item, error) {
 	newItem, err := todotxt.BuildItem(
-		todotxt.WithDescription(strings.TrimSpace(fmt.Sprintf("%s %s %s", a.def.AddPrefix, description, a.def.AddSuffix))),
-		todotxt.WithCreationDate(time.Now()),
+		todotxt.WithDescription(strings.TrimSpace(fmt.Sprintf("%s %s %s", def.AddPrefix, description, def.AddSuffix))),
+		todotxt.WithCreationDate(now()),
 		todotxt.WithPriority(prio),
 	)
 	if err != nil {
-		return fmt.Errorf("could not create task: %w", err)
+		return nil, fmt.Errorf("could not create task: %w", err)
 	}
 	err = list.Add(newItem)
 	if err != nil {
-		return fmt.Errorf("could not add task: %w", err)
+		return nil, fmt.Errorf("could not add task: %w", err)
 	}
-	fmt.Printf("Added task #%d\n", list.LineOf(newItem))
-	return nil
+	return newItem, nil
 }
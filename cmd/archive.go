@@ -16,11 +16,9 @@ var doneFunc qselect.Func = func(l *todotxt.List, i *todotxt.Item) bool {
 }
 
 type archiveCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
-	all     bool
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	all       bool
 }
 
 func newArchiveCommand(def di.ViewDef) *archiveCommand {
@@ -40,14 +38,15 @@ func (a *archiveCommand) command() *cobra.Command {
 		RunE:     a.archive,
 		PostRunE: cmdutil.Steps(cmdutil.SaveDoneList, cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(archiveCommand, &a.qql, &a.rng, &a.str, &a.all)
+	cmdutil.RegisterSelectionFlags(archiveCommand, &a.selection, &a.all)
 	return archiveCommand
 }
 
 func (a *archiveCommand) archive(cmd *cobra.Command, args []string) error {
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
 	doneList := cmd.Context().Value(cmdutil.DoneListKey).(*todotxt.List)
-	selector, err := cmdutil.ParseTaskSelection(a.viewDef.Query, args, a.qql, a.rng, a.str)
+	doneUnion := cmd.Context().Value(cmdutil.DoneUnionKey).(*todotxt.Union)
+	selector, err := cmdutil.ParseTaskSelection(a.viewDef.Query, args, a.selection)
 	if err != nil {
 		return err
 	}
@@ -64,15 +63,27 @@ func (a *archiveCommand) archive(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	for _, t := range confirmedSelection {
+	if err := archiveTasks(list, doneList, doneUnion, confirmedSelection); err != nil {
+		return err
+	}
+	view.NewSuccessMessage("Archived", list, confirmedSelection).Run()
+	return nil
+}
+
+// archiveTasks moves tasks from list to doneList
+func archiveTasks(list *todotxt.List, doneList *todotxt.List, doneUnion *todotxt.Union, tasks []*todotxt.Item) error {
+	for _, t := range tasks {
+		// Archive into the done file of the workspace the task came from
+		source := list.SourceOf(t)
 		if err := list.Remove(list.LineOf(t)); err != nil {
 			return err
 		}
 		if err := doneList.Add(t); err != nil {
 			return err
 		}
+		if source != nil {
+			doneList.SetSource(doneUnion.RepoNamed(source.Name), t)
+		}
 	}
-
-	view.NewSuccessMessage("Archived", list, confirmedSelection).Run()
 	return nil
 }
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qduration"
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	"github.com/spf13/cobra"
+)
+
+var chartDefaultSince = map[string]string{
+	"open":    "12w",
+	"weekday": "",
+	"heatmap": "26w",
+}
+
+type chartCommand struct {
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	period    string
+	since     string
+	by        string
+	json      bool
+}
+
+func newChartCommand(def di.ViewDef) *chartCommand {
+	cmd := chartCommand{
+		viewDef: def,
+	}
+
+	return &cmd
+}
+
+func (c *chartCommand) command() *cobra.Command {
+	var chartCommand = &cobra.Command{
+		Use:   "chart {open|weekday|heatmap} [selectors...]",
+		Short: "Renders charts about the matching tasks of todo.txt and done.txt",
+		Long: `Chart renders one of the following charts for the matching tasks of your todo.txt and done.txt:
+
+open:    The number of open tasks over time (optionally one line per project or context with --by)
+weekday: The number of completed tasks per weekday
+heatmap: A heatmap of the completed tasks per day`,
+		Example: "quest chart open --by project --period day --since 4w\nquest chart heatmap -q '+quest'",
+		GroupID: "view-cmd",
+		Args:    cobra.MinimumNArgs(1),
+		ValidArgs: []string{
+			"open", "weekday", "heatmap",
+		},
+		PreRunE: cmdutil.Steps(cmdutil.LoadSource(di.SourceBoth)),
+		RunE:    c.chart,
+	}
+	chartCommand.Flags().StringVar(&c.period, "period", "week", "The period of a single data point of the open chart. One of day, week or month")
+	chartCommand.Flags().StringVar(&c.since, "since", "", "How far the chart should reach into the past (default 12w for open and 26w for heatmap)")
+	chartCommand.Flags().StringVar(&c.by, "by", "", "Split the open chart by project or context")
+	chartCommand.Flags().BoolVar(&c.json, "json", false, "Output the data of the chart in json format")
+	cmdutil.RegisterSelectionFlags(chartCommand, &c.selection, nil)
+	return chartCommand
+}
+
+func (c *chartCommand) chart(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	kind := args[0]
+	defaultSince, ok := chartDefaultSince[kind]
+	if !ok {
+		return fmt.Errorf("unknown chart %s. Must be one of open, weekday or heatmap", kind)
+	}
+	selector, err := cmdutil.ParseTaskSelection(c.viewDef.Query, args[1:], c.selection)
+	if err != nil {
+		return err
+	}
+	period, err := qstats.ParsePeriod(c.period)
+	if err != nil {
+		return err
+	}
+	now := di.Config().NowFunc
+	begin := now()
+	if since := c.since; since != "" || defaultSince != "" {
+		if since == "" {
+			since = defaultSince
+		}
+		d, err := qduration.Parse(since)
+		if err != nil {
+			return fmt.Errorf("invalid duration for --since: %w", err)
+		}
+		begin = d.SubFrom(now())
+	}
+	calc := qstats.Calculator{
+		Period:  period,
+		Begin:   begin,
+		NowFunc: now,
+	}
+	selection := selector.Filter(list)
+
+	var data any
+	var rendered string
+	switch kind {
+	case "open":
+		series, err := c.openSeries(calc, selection)
+		if err != nil {
+			return err
+		}
+		data, rendered = series, view.Sparklines(series)
+	case "weekday":
+		perWeekday := qstats.CompletionsPerWeekday(selection)
+		data, rendered = perWeekday, view.BarChart([]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}, perWeekday[:], 40)
+	case "heatmap":
+		perDay := qstats.CompletionsPerDay(selection)
+		jsonData := make(map[string]int)
+		for day, v := range perDay {
+			jsonData[day.Format(time.DateOnly)] = v
+		}
+		data, rendered = jsonData, view.Heatmap(perDay, begin, now())
+	}
+
+	if c.json {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(data)
+	}
+	fmt.Print(rendered)
+	return nil
+}
+
+func (c *chartCommand) openSeries(calc qstats.Calculator, selection []*todotxt.Item) ([]qstats.Series, error) {
+	switch c.by {
+	case "":
+		return []qstats.Series{calc.OpenSeries("open", selection)}, nil
+	case "project":
+		return calc.OpenSeriesBy(selection, qstats.Projects), nil
+	case "context":
+		return calc.OpenSeriesBy(selection, qstats.Contexts), nil
+	default:
+		return nil, fmt.Errorf("can not split chart by %s. Must be one of project or context", c.by)
+	}
+}
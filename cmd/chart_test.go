@@ -0,0 +1,28 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ChartWeekdayCountsCompletionsOfTheSelection(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("x 2022-01-31 2022-01-01 done on monday +quest\n"), 0644))
+	assert.Nil(t, os.WriteFile(cfg.DoneFile, []byte("x 2022-02-01 2022-01-01 done on tuesday +quest\nx 2022-02-01 2022-01-01 other project +other\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "chart", "weekday", "--json", "-q", "+quest")
+	assert.Nil(t, err)
+	perWeekday := [7]int{}
+	assert.Nil(t, json.Unmarshal([]byte(out), &perWeekday))
+	assert.Equal(t, [7]int{1, 1, 0, 0, 0, 0, 0}, perWeekday)
+}
+
+func Test_ChartFailsForUnknownKind(t *testing.T) {
+	cfg := BuildTestConfig(t)
+
+	_, err := runWithOutput(t, cfg, "chart", "pie")
+	assert.Error(t, err)
+}
@@ -8,20 +8,26 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qduration"
 	"github.com/Fabian-G/quest/qselect"
 	"github.com/Fabian-G/quest/todotxt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 type CtxKey string
 
 var (
-	DiKey       CtxKey = "DI"
-	ListKey     CtxKey = "list"
-	DoneListKey CtxKey = "done-list"
+	DiKey        CtxKey = "DI"
+	ListKey      CtxKey = "list"
+	UnionKey     CtxKey = "union"
+	DoneListKey  CtxKey = "done-list"
+	DoneUnionKey CtxKey = "done-union"
 )
 
 func Steps(steps ...func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
@@ -35,10 +41,34 @@ func Steps(steps ...func(*cobra.Command, []string) error) func(*cobra.Command, [
 	}
 }
 
+// SetupSteps returns the steps that prepare every command, e.g. by registering the configured tags and functions.
+// Commands that run them on their own (instead of the ones of the root command) should add new steps here.
+func SetupSteps() []func(*cobra.Command, []string) error {
+	return []func(*cobra.Command, []string) error{
+		ConfigOverrides,
+		EnsureTodoFileExits,
+		EnsureDoneFileExists,
+		EnsureNotesDirExists,
+		RegisterIdTag,
+		RegisterDependencyTag,
+		RegisterSubtaskTag,
+		RegisterHolidays,
+		RegisterFunctions,
+		RegisterMacros,
+		RegisterNotifications,
+	}
+}
+
 func ConfigOverrides(cmd *cobra.Command, args []string) error {
 	di := cmd.Context().Value(DiKey).(*di.Container)
 	cfg := di.Config()
 
+	workspace := cmd.Root().PersistentFlags().Lookup("workspace")
+	if workspace.Changed {
+		if err := cfg.UseWorkspace(workspace.Value.String()); err != nil {
+			return err
+		}
+	}
 	file := cmd.Root().PersistentFlags().Lookup("file")
 	if file.Changed {
 		cfg.TodoFile = file.Value.String()
@@ -48,42 +78,209 @@ func ConfigOverrides(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// LoadList reads the tasks of all workspaces of the current view.
 func LoadList(cmd *cobra.Command, args []string) error {
-	repo := cmd.Context().Value(DiKey).(*di.Container).TodoTxtRepo()
-	list, err := repo.Read()
-	if err != nil {
-		return err
+	return LoadSource(di.SourceTodo)(cmd, args)
+}
+
+// LoadSource returns a step that reads the tasks of the todo files, the done files
+// or both (depending on source) of all workspaces of the current view.
+func LoadSource(source string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		container := cmd.Context().Value(DiKey).(*di.Container)
+		repos := make([]*todotxt.Repo, 0)
+		if source == di.SourceTodo || source == di.SourceBoth {
+			todo, err := viewUnion(cmd, container.TodoTxtRepo(), container.WorkspaceRepo)
+			if err != nil {
+				return err
+			}
+			repos = append(repos, todo.Repos()...)
+		}
+		if source == di.SourceDone || source == di.SourceBoth {
+			done, err := viewUnion(cmd, container.DoneTxtRepo(), container.WorkspaceDoneRepo)
+			if err != nil {
+				return err
+			}
+			repos = append(repos, done.Repos()...)
+		}
+		if len(repos) == 0 {
+			return fmt.Errorf("unknown source %s. Must be one of %s, %s or %s", source, di.SourceTodo, di.SourceDone, di.SourceBoth)
+		}
+		union := todotxt.NewUnion(repos...)
+		list, err := union.Read()
+		if err != nil {
+			return err
+		}
+		cmd.SetContext(context.WithValue(cmd.Context(), UnionKey, union))
+		cmd.SetContext(context.WithValue(cmd.Context(), ListKey, list))
+		return nil
 	}
-	cmd.SetContext(context.WithValue(cmd.Context(), ListKey, list))
-	return nil
 }
 
+// SaveList writes every task back to the todo file it was read from.
 func SaveList(cmd *cobra.Command, args []string) error {
-	repo := cmd.Context().Value(DiKey).(*di.Container).TodoTxtRepo()
+	union := cmd.Context().Value(UnionKey).(*todotxt.Union)
 	list := cmd.Context().Value(ListKey).(*todotxt.List)
-	if err := repo.Save(list); err != nil {
+	if err := SaveUnion(cmd, args, union, list); err != nil {
 		return fmt.Errorf("could not save todo file: %w", err)
 	}
-	return repo.Close()
+	return union.Close()
 }
 
+// LoadDoneList reads the done tasks of all workspaces whose todo files have been loaded (see LoadList).
 func LoadDoneList(cmd *cobra.Command, args []string) error {
-	repo := cmd.Context().Value(DiKey).(*di.Container).DoneTxtRepo()
-	list, err := repo.Read()
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	todo := cmd.Context().Value(UnionKey).(*todotxt.Union)
+	workspaces := make([]string, 0, len(todo.Repos()))
+	for _, r := range todo.Repos() {
+		workspaces = append(workspaces, r.Name)
+	}
+	union, err := workspaceUnion(cmd, workspaces, di.DoneTxtRepo(), di.WorkspaceDoneRepo)
+	if err != nil {
+		return err
+	}
+	list, err := union.Read()
 	if err != nil {
 		return err
 	}
+	cmd.SetContext(context.WithValue(cmd.Context(), DoneUnionKey, union))
 	cmd.SetContext(context.WithValue(cmd.Context(), DoneListKey, list))
 	return nil
 }
 
+// SaveDoneList writes every task back to the done file it was read from.
 func SaveDoneList(cmd *cobra.Command, args []string) error {
-	repo := cmd.Context().Value(DiKey).(*di.Container).DoneTxtRepo()
+	union := cmd.Context().Value(DoneUnionKey).(*todotxt.Union)
 	list := cmd.Context().Value(DoneListKey).(*todotxt.List)
-	if err := repo.Save(list); err != nil {
+	if err := SaveUnion(cmd, args, union, list); err != nil {
 		return fmt.Errorf("could not save done file: %w", err)
 	}
-	return repo.Close()
+	return union.Close()
+}
+
+// SaveUnion writes every task of list back to the repo of union it was read from and records the changes in the journal.
+func SaveUnion(cmd *cobra.Command, args []string, union *todotxt.Union, list *todotxt.List) error {
+	return SaveUnionAs(cmd, commandLine(cmd, args), union, list)
+}
+
+// SaveUnionAs is like SaveUnion, but records the changes under the given command
+func SaveUnionAs(cmd *cobra.Command, command string, union *todotxt.Union, list *todotxt.List) error {
+	save := func() error { return union.Save(list) }
+	for _, repo := range union.Repos() {
+		inner, file := save, repo.File()
+		save = func() error { return RecordChangesAs(cmd, command, file, inner) }
+	}
+	return save()
+}
+
+// ActionCommand describes an action of an interactive view in the journal (e.g. "quest: Toggled 1 task(s)")
+func ActionCommand(cmd *cobra.Command, args []string, action string) string {
+	return fmt.Sprintf("%s: %s", commandLine(cmd, args), action)
+}
+
+// TodoUnion returns the union of the todo files of the given workspaces (or of the current one if there are none).
+func TodoUnion(cmd *cobra.Command, workspaces []string) (*todotxt.Union, error) {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	return workspaceUnion(cmd, workspaces, di.TodoTxtRepo(), di.WorkspaceRepo)
+}
+
+// viewUnion returns the union of the repos of all workspaces that are part of the current view.
+func viewUnion(cmd *cobra.Command, current *todotxt.Repo, repoOf func(string) (*todotxt.Repo, error)) (*todotxt.Union, error) {
+	return workspaceUnion(cmd, viewDefOf(cmd).Workspaces, current, repoOf)
+}
+
+// workspaceUnion returns the union of the repos of the given workspaces or just current if there are none.
+// The first one is the current workspace if it is one of them.
+func workspaceUnion(cmd *cobra.Command, workspaces []string, current *todotxt.Repo, repoOf func(string) (*todotxt.Repo, error)) (*todotxt.Union, error) {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	workspaces = slices.Clone(workspaces)
+	if len(workspaces) == 0 {
+		return todotxt.NewUnion(current), nil
+	}
+	if idx := slices.Index(workspaces, di.Config().Workspace); idx != -1 {
+		workspaces = slices.Insert(slices.Delete(workspaces, idx, idx+1), 0, di.Config().Workspace)
+	}
+	repos := make([]*todotxt.Repo, 0, len(workspaces))
+	for _, w := range workspaces {
+		repo, err := repoOf(w)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(repos, repo) {
+			continue
+		}
+		if err := createFileIfNotExists(repo.File()); err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+	return todotxt.NewUnion(repos...), nil
+}
+
+// viewDefOf returns the definition of the view cmd belongs to
+func viewDefOf(cmd *cobra.Command) di.ViewDef {
+	cfg := cmd.Context().Value(DiKey).(*di.Container).Config()
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.GroupID != "view" {
+			continue
+		}
+		if def, ok := cfg.Views[c.Name()]; ok {
+			return def
+		}
+	}
+	return cfg.DefaultView
+}
+
+// RecordChanges runs save and writes the resulting changes of file to the journal
+func RecordChanges(cmd *cobra.Command, args []string, file string, save func() error) error {
+	return RecordChangesAs(cmd, commandLine(cmd, args), file, save)
+}
+
+// RecordChangesAs is like RecordChanges, but records the changes under the given command
+func RecordChangesAs(cmd *cobra.Command, command string, file string, save func() error) error {
+	journal := cmd.Context().Value(DiKey).(*di.Container).Journal()
+	if !journal.Enabled() {
+		return save()
+	}
+	before, err := ReadLines(file)
+	if err != nil {
+		return err
+	}
+	if err := save(); err != nil {
+		return err
+	}
+	after, err := ReadLines(file)
+	if err != nil {
+		return err
+	}
+	if err := journal.Record(command, file, before, after); err != nil {
+		return fmt.Errorf("could not record changes in history: %w", err)
+	}
+	return nil
+}
+
+func commandLine(cmd *cobra.Command, args []string) string {
+	parts := []string{cmd.CommandPath()}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		parts = append(parts, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return strings.Join(append(parts, args...), " ")
+}
+
+// ReadLines returns the lines of file without the trailing newline
+func ReadLines(file string) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	content := strings.TrimSuffix(string(data), "\n")
+	if len(content) == 0 {
+		return nil, nil
+	}
+	return strings.Split(content, "\n"), nil
 }
 
 func createFileIfNotExists(file string) error {
@@ -130,6 +327,55 @@ func EnsureNotesDirExists(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+func RegisterIdTag(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	qselect.RegisterIdTag(di.Config().Ids.Tag)
+	return nil
+}
+
+func RegisterDependencyTag(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	return qselect.RegisterDependencyTag(di.Config().Dependencies.Tag)
+}
+
+func RegisterSubtaskTag(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	return qselect.RegisterSubtaskTag(di.Config().Subtasks.Tag)
+}
+
+func RegisterHolidays(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	if di.Config().Holidays == "" {
+		qduration.SetHolidays()
+		return nil
+	}
+	file, err := os.Open(di.Config().Holidays)
+	if err != nil {
+		return fmt.Errorf("could not open holidays file: %w", err)
+	}
+	defer file.Close()
+	holidays, err := qduration.ReadHolidays(file)
+	if err != nil {
+		return fmt.Errorf("could not read holidays file %s: %w", di.Config().Holidays, err)
+	}
+	qduration.SetHolidays(holidays...)
+	return nil
+}
+
+func RegisterFunctions(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	qselect.SetScriptErrorReporter(func(err error) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", err)
+	})
+	for _, function := range di.Config().Functions {
+		err := qselect.RegisterScriptFunction(function.Name, function.Script, function.InDTypes(), qselect.DType(function.ResultType))
+		if err != nil {
+			return fmt.Errorf("could not register function %s: %w", function.Name, err)
+		}
+	}
+	return nil
+}
+
 func RegisterMacros(cmd *cobra.Command, args []string) error {
 	di := cmd.Context().Value(DiKey).(*di.Container)
 	for _, macro := range di.Config().Macros {
@@ -141,33 +387,95 @@ func RegisterMacros(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// RegisterNotifications prints the notifications of the hooks (e.g. that a task is no longer blocked).
+// Interactive views replace this, because the terminal belongs to them.
+func RegisterNotifications(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	di.SetNotify(func(msg string) {
+		fmt.Fprintln(cmd.OutOrStdout(), msg)
+	})
+	return nil
+}
+
+// AutoSyncRecurrence spawns the upcoming occurrences of recurrent tasks in the todo file
+// before every view command, if the auto-sync option is enabled.
+// Global commands are skipped, because otherwise undo would revert the sync instead of the last command.
+// The sync is recorded as a journal entry of its own, so that undoing the command keeps the spawned tasks.
+func AutoSyncRecurrence(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(DiKey).(*di.Container)
+	if !di.Config().Recurrence.AutoSync || di.Recurrence() == nil || cmd.GroupID == "global-cmd" {
+		return nil
+	}
+	repo := di.TodoTxtRepo()
+	list, err := repo.Read()
+	if err != nil {
+		return err
+	}
+	spawned, err := SyncRecurrence(di, list, di.Config().Recurrence.Horizon)
+	if err != nil {
+		return fmt.Errorf("could not sync recurrent tasks: %w", err)
+	}
+	if len(spawned) == 0 {
+		return nil
+	}
+	journal := di.Journal()
+	journal.NewInvocation()
+	defer journal.NewInvocation()
+	return RecordChangesAs(cmd, "quest recur sync (auto-sync)", repo.File(), func() error { return repo.Save(list) })
+}
+
+// SyncRecurrence spawns the occurrences of the recurrent tasks of list up to today + horizon.
+func SyncRecurrence(di *di.Container, list *todotxt.List, horizon string) ([]*todotxt.Item, error) {
+	if di.Recurrence() == nil {
+		return nil, errors.New("recurrence is not enabled. Set the rec-tag in the recurrence section of the config file")
+	}
+	duration, err := qduration.Parse(horizon)
+	if err != nil {
+		return nil, fmt.Errorf("invalid horizon %s: %w", horizon, err)
+	}
+	now := time.Now()
+	if di.Config().NowFunc != nil {
+		now = di.Config().NowFunc()
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return di.Recurrence().Sync(list, duration.AddTo(today))
+}
+
 func SyncConflictProtection(cmd *cobra.Command, args []string) error {
 	v := cmd.Context().Value(DiKey).(*di.Container).Config()
-	file := v.TodoFile
-	filesInDir, err := os.ReadDir(path.Dir(file))
+	conflicts, err := SyncConflicts(v.TodoFile)
 	if err != nil {
 		return fmt.Errorf("could not check for sync conflicts: %w", err)
 	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	fmt.Printf("The following sync conflicts have been detected:\n")
+	for _, c := range conflicts {
+		fmt.Printf("- %s\n", c)
+	}
+	fmt.Println("\nPlease run \"quest merge-conflicts\" or merge manually and then remove the specified files.")
+	return errors.New("sync conflict")
+}
+
+// SyncConflicts returns the conflict files created by Syncthing or quest itself for file
+func SyncConflicts(file string) ([]string, error) {
+	filesInDir, err := os.ReadDir(path.Dir(file))
+	if err != nil {
+		return nil, err
+	}
 
 	base := path.Base(file)
 	extension := path.Ext(file)
 	name := strings.TrimSuffix(base, extension)
-	syncthingConflictMatcher := regexp.MustCompile(fmt.Sprintf("^%s\\.sync-conflict-.*%s$", name, extension))
-	questOLockConflictMatcher := regexp.MustCompile(fmt.Sprintf("^%s\\.quest-conflict-.*%s$", name, extension))
+	syncthingConflictMatcher := regexp.MustCompile(fmt.Sprintf("^%s\\.sync-conflict-.*%s$", regexp.QuoteMeta(name), regexp.QuoteMeta(extension)))
+	questOLockConflictMatcher := regexp.MustCompile(fmt.Sprintf("^%s\\.quest-conflict-.*%s$", regexp.QuoteMeta(name), regexp.QuoteMeta(extension)))
 	conflicts := make([]string, 0)
 	for _, f := range filesInDir {
 		if syncthingConflictMatcher.MatchString(path.Base(f.Name())) || questOLockConflictMatcher.MatchString(path.Base(f.Name())) {
 			conflicts = append(conflicts, path.Join(path.Dir(file), f.Name()))
 		}
 	}
-	if len(conflicts) == 0 {
-		return nil
-	}
-
-	fmt.Printf("The following sync conflicts have been detected:\n")
-	for _, c := range conflicts {
-		fmt.Printf("- %s\n", c)
-	}
-	fmt.Println("\nPlease merge manually and then remove the specified files.")
-	return errors.New("sync conflict")
+	return conflicts, nil
 }
@@ -2,21 +2,37 @@ package cmdutil
 
 import (
 	"fmt"
+	"runtime"
 
+	"github.com/Fabian-G/quest/di"
 	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/todotxt"
 	"github.com/spf13/cobra"
 )
 
-func RegisterSelectionFlags(cmd *cobra.Command, qql *[]string, rng *[]string, str *[]string, all *bool) {
-	cmd.Flags().StringArrayVarP(qql, "qql", "q", nil, "QQL Query")
-	cmd.Flags().StringArrayVarP(rng, "range", "r", nil, "Range Query")
-	cmd.Flags().StringArrayVarP(str, "word", "w", nil, "Case-Insensitive String Search")
+// Selection holds the selectors that are given by flags.
+// New kinds of selectors are added here, so that the commands do not have to change.
+type Selection struct {
+	QQL   []string
+	Range []string
+	Id    []string
+	Word  []string
+	Fuzzy []string
+}
+
+func RegisterSelectionFlags(cmd *cobra.Command, selection *Selection, all *bool) {
+	cmd.Flags().StringArrayVarP(&selection.QQL, "qql", "q", nil, "QQL Query")
+	cmd.Flags().StringArrayVarP(&selection.Range, "range", "r", nil, "Range Query")
+	cmd.Flags().StringArrayVarP(&selection.Id, "id", "I", nil, "Comma separated list of task ids")
+	cmd.Flags().StringArrayVarP(&selection.Word, "word", "w", nil, "Case-Insensitive String Search")
+	cmd.Flags().StringArrayVarP(&selection.Fuzzy, "fuzzy", "z", nil, "Fuzzy Search on description, projects and contexts")
 	if all != nil {
 		cmd.Flags().BoolVarP(all, "all", "a", false, "Don't ask for confirmation when multiple results match")
 	}
 }
 
-func ParseTaskSelection(defaultQuery string, guess, qqlSearch, rngSearch, stringSearch []string) (qselect.Func, error) {
+// ParseTaskSelection combines the default query, the guessed positional selectors and the selection into one query
+func ParseTaskSelection(defaultQuery string, guess []string, selection Selection) (qselect.Func, error) {
 	selectors := make([]qselect.Func, 0)
 	q, err := qselect.CompileQQL(defaultQuery)
 	if err != nil {
@@ -30,27 +46,50 @@ func ParseTaskSelection(defaultQuery string, guess, qqlSearch, rngSearch, string
 		}
 		selectors = append(selectors, q)
 	}
-	for _, f := range qqlSearch {
+	for _, f := range selection.QQL {
 		q, err := qselect.CompileQQL(f)
 		if err != nil {
 			return nil, fmt.Errorf("could not compile QQL query %s: %w", f, err)
 		}
 		selectors = append(selectors, q)
 	}
-	for _, r := range rngSearch {
+	for _, r := range selection.Range {
 		q, err := qselect.CompileRange(r)
 		if err != nil {
 			return nil, fmt.Errorf("could not compile range query %s: %w", r, err)
 		}
 		selectors = append(selectors, q)
 	}
-	for _, s := range stringSearch {
+	for _, i := range selection.Id {
+		q, err := qselect.CompileIdSelection(i)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile id query %s: %w", i, err)
+		}
+		selectors = append(selectors, q)
+	}
+	for _, s := range selection.Word {
 		q, err := qselect.CompileWordSearch(s)
 		if err != nil {
 			return nil, fmt.Errorf("could not compile string search query %s: %w", s, err)
 		}
 		selectors = append(selectors, q)
 	}
+	for _, f := range selection.Fuzzy {
+		q, err := qselect.CompileFuzzySearch(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile fuzzy search query %s: %w", f, err)
+		}
+		selectors = append(selectors, q)
+	}
 
 	return qselect.And(selectors...), nil
 }
+
+// Filter returns the tasks of list that match query. Large lists are filtered
+// concurrently if parallel-filter is configured.
+func Filter(config di.Config, query qselect.Func, list *todotxt.List) []*todotxt.Item {
+	if config.Parallel > 0 && list.Len() >= config.Parallel {
+		return query.FilterParallel(list, runtime.GOMAXPROCS(0))
+	}
+	return query.Filter(list)
+}
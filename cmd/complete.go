@@ -16,11 +16,9 @@ var notDoneFunc qselect.Func = func(l *todotxt.List, i *todotxt.Item) bool {
 }
 
 type completeCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
-	all     bool
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	all       bool
 }
 
 func newCompleteCommand(def di.ViewDef) *completeCommand {
@@ -40,13 +38,13 @@ func (c *completeCommand) command() *cobra.Command {
 		RunE:     c.complete,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(completeCmd, &c.qql, &c.rng, &c.str, &c.all)
+	cmdutil.RegisterSelectionFlags(completeCmd, &c.selection, &c.all)
 	return completeCmd
 }
 
 func (c *completeCommand) complete(cmd *cobra.Command, args []string) error {
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
-	selector, err := cmdutil.ParseTaskSelection(c.viewDef.Query, args, c.qql, c.rng, c.str)
+	selector, err := cmdutil.ParseTaskSelection(c.viewDef.Query, args, c.selection)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/hook"
+	"github.com/Fabian-G/quest/qduration"
+	"github.com/Fabian-G/quest/qprojection"
+	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/qsort"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	"github.com/spf13/cobra"
+)
+
+type configCommand struct{}
+
+func newConfigCommand() *configCommand {
+	return &configCommand{}
+}
+
+func (c *configCommand) command() *cobra.Command {
+	var configCommand = &cobra.Command{
+		Use:     "config",
+		Short:   "Commands for working with the config file",
+		GroupID: "global-cmd",
+		// The checks must not fail before the config has been checked
+		PersistentPreRunE: cmdutil.Steps(cmdutil.ConfigOverrides, cmdutil.RegisterIdTag),
+	}
+	configCommand.AddCommand(&cobra.Command{
+		Use:   "check",
+		Short: "Checks all queries, sort keys and projections of the config file",
+		Long: `Check compiles every view query, macro, style condition, sort key and projection of the config file.
+All problems are reported at once and the command exits with a non-zero exit code if there are any.`,
+		Args: cobra.NoArgs,
+		RunE: c.check,
+	})
+	return configCommand
+}
+
+type configProblem struct {
+	key   string
+	query string
+	err   error
+}
+
+func (p configProblem) String() string {
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "%s: %s\n", p.key, p.err)
+	var posErr qselect.PositionError
+	if p.query != "" && errors.As(p.err, &posErr) {
+		fmt.Fprintf(&builder, "\t%s\n", p.query)
+		fmt.Fprintf(&builder, "\t%s^\n", strings.Repeat(" ", len([]rune(p.query[:min(posErr.Pos, len(p.query))]))))
+	}
+	return builder.String()
+}
+
+func (c *configCommand) check(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	problems := checkConfig(di.Config())
+	for _, p := range problems {
+		fmt.Fprint(cmd.OutOrStdout(), p.String())
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in the config file", len(problems))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "No problems found")
+	return nil
+}
+
+func checkConfig(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	problems = append(problems, checkTags(config)...)
+	problems = append(problems, checkHolidays(config)...)
+	problems = append(problems, checkDependencies(config)...)
+	problems = append(problems, checkSubtasks(config)...)
+	if _, err := qduration.Parse(config.Recurrence.Horizon); config.Recurrence.Horizon != "" && err != nil {
+		problems = append(problems, configProblem{key: "recurrence.horizon", err: err})
+	}
+	problems = append(problems, checkFunctions(config)...)
+	problems = append(problems, checkMacros(config)...)
+	for i, s := range config.Styles {
+		problems = append(problems, checkQuery(fmt.Sprintf("styles[%d].if", i), s.If)...)
+	}
+	problems = append(problems, checkView(config, "default-view", config.DefaultView)...)
+	viewNames := make([]string, 0, len(config.Views))
+	for name := range config.Views {
+		viewNames = append(viewNames, name)
+	}
+	slices.Sort(viewNames)
+	for _, name := range viewNames {
+		problems = append(problems, checkView(config, fmt.Sprintf("views.%s", name), config.Views[name])...)
+	}
+	problems = append(problems, checkDashboard(config)...)
+	return problems
+}
+
+func checkDashboard(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	if config.Dashboard.Layout != "" && !slices.Contains(view.Layouts, config.Dashboard.Layout) {
+		problems = append(problems, configProblem{key: "dashboard.layout", err: fmt.Errorf("unknown layout %s. Must be one of %v", config.Dashboard.Layout, view.Layouts)})
+	}
+	for _, name := range config.Dashboard.Panes {
+		def, ok := dashboardView(config, name)
+		if !ok {
+			problems = append(problems, configProblem{key: "dashboard.panes", err: fmt.Errorf("unknown view %s", name)})
+			continue
+		}
+		if err := checkPaneSource(def); err != nil {
+			problems = append(problems, configProblem{key: "dashboard.panes", err: fmt.Errorf("view %s: %w", name, err)})
+		}
+	}
+	return problems
+}
+
+func checkTags(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	tags := make([]string, 0, len(config.Tags))
+	for tag := range config.Tags {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		tagDef := config.Tags[tag]
+		key := fmt.Sprintf("tags.%s", tag)
+		if tagDef.Type != "" && !slices.Contains(qselect.AllDTypes, qselect.DType(tagDef.Type)) {
+			problems = append(problems, configProblem{key: key + ".type", err: fmt.Errorf("unknown type %s. Allowed types are: %v", tagDef.Type, qselect.AllDTypes)})
+		}
+		for i, s := range tagDef.Styles {
+			problems = append(problems, checkQuery(fmt.Sprintf("%s.styles[%d].if", key, i), s.If)...)
+		}
+	}
+	return problems
+}
+
+func checkHolidays(config di.Config) []configProblem {
+	if config.Holidays == "" {
+		return nil
+	}
+	file, err := os.Open(config.Holidays)
+	if err != nil {
+		return []configProblem{{key: "holidays-file", err: err}}
+	}
+	defer file.Close()
+	if _, err := qduration.ReadHolidays(file); err != nil {
+		return []configProblem{{key: "holidays-file", err: err}}
+	}
+	return nil
+}
+
+// checkDependencies registers the dependency tag, just like the RegisterDependencyTag step does.
+func checkDependencies(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	if err := qselect.RegisterDependencyTag(config.Dependencies.Tag); err != nil {
+		problems = append(problems, configProblem{key: "dependencies.tag", err: err})
+	}
+	switch config.Dependencies.OnComplete {
+	case "", hook.OnCompleteNotify, hook.OnCompleteUnblock:
+	default:
+		problems = append(problems, configProblem{key: "dependencies.on-complete", err: fmt.Errorf("unknown action %s. Allowed actions are: %s, %s", config.Dependencies.OnComplete, hook.OnCompleteNotify, hook.OnCompleteUnblock)})
+	}
+	return problems
+}
+
+// checkSubtasks registers the subtask tag, just like the RegisterSubtaskTag step does.
+func checkSubtasks(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	if err := qselect.RegisterSubtaskTag(config.Subtasks.Tag); err != nil {
+		problems = append(problems, configProblem{key: "subtasks.tag", err: err})
+	}
+	switch config.Subtasks.ParentCompletion {
+	case "", hook.ParentCompletionAuto, hook.ParentCompletionStrict:
+	default:
+		problems = append(problems, configProblem{key: "subtasks.parent-completion", err: fmt.Errorf("unknown mode %s. Allowed modes are: %s, %s", config.Subtasks.ParentCompletion, hook.ParentCompletionAuto, hook.ParentCompletionStrict)})
+	}
+	return problems
+}
+
+// checkFunctions registers all script functions, just like the RegisterFunctions step does.
+func checkFunctions(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	for i, function := range config.Functions {
+		key := fmt.Sprintf("function[%d] (%s)", i, function.Name)
+		types := append(function.InDTypes(), qselect.DType(function.ResultType))
+		if idx := slices.IndexFunc(types, func(t qselect.DType) bool { return !slices.Contains(qselect.AllDTypes, t) }); idx >= 0 {
+			problems = append(problems, configProblem{key: key, err: fmt.Errorf("unknown type %s. Allowed types are: %v", types[idx], qselect.AllDTypes)})
+			continue
+		}
+		if err := qselect.RegisterScriptFunction(function.Name, function.Script, function.InDTypes(), qselect.DType(function.ResultType)); err != nil {
+			problems = append(problems, configProblem{key: key + ".script", err: err})
+		}
+	}
+	return problems
+}
+
+// checkMacros registers all macros, just like the RegisterMacros step does.
+func checkMacros(config di.Config) []configProblem {
+	problems := make([]configProblem, 0)
+	for i, macro := range config.Macros {
+		key := fmt.Sprintf("macro[%d] (%s)", i, macro.Name)
+		types := append(macro.InDTypes(), qselect.DType(macro.ResultType))
+		if idx := slices.IndexFunc(types, func(t qselect.DType) bool { return !slices.Contains(qselect.AllDTypes, t) }); idx >= 0 {
+			problems = append(problems, configProblem{key: key, err: fmt.Errorf("unknown type %s. Allowed types are: %v", types[idx], qselect.AllDTypes)})
+			continue
+		}
+		err := qselect.RegisterMacro(macro.Name, macro.Query, macro.InDTypes(), qselect.DType(macro.ResultType), macro.InjectIt)
+		if err != nil {
+			problems = append(problems, configProblem{key: key + ".query", query: macro.Query, err: err})
+		}
+	}
+	return problems
+}
+
+func checkView(config di.Config, key string, view di.ViewDef) []configProblem {
+	problems := checkQuery(key+".query", view.Query)
+	sortCompiler := qsort.Compiler{TagTypes: config.TagTypes()}
+	if _, err := sortCompiler.CompileSortFunc(view.Sort); err != nil {
+		problems = append(problems, configProblem{key: key + ".sort", err: err})
+	}
+	projector := qprojection.Projector{
+		HumanizedTags: config.HumanizedTags(),
+		TagTypes:      config.TagTypes(),
+		IdTag:         config.Ids.Tag,
+	}
+	if err := projector.Verify(view.Projection, todotxt.ListOf()); err != nil {
+		problems = append(problems, configProblem{key: key + ".projection", err: err})
+	}
+	for _, ws := range view.Workspaces {
+		if _, ok := config.Workspaces[ws]; !ok {
+			problems = append(problems, configProblem{key: key + ".workspaces", err: fmt.Errorf("unknown workspace %s", ws)})
+		}
+	}
+	if !slices.Contains([]string{"", di.SourceTodo, di.SourceDone, di.SourceBoth}, view.Source) {
+		problems = append(problems, configProblem{key: key + ".source", err: fmt.Errorf("unknown source %s. Must be one of todo, done or both", view.Source)})
+	}
+	return problems
+}
+
+func checkQuery(key string, query string) []configProblem {
+	if _, err := qselect.CompileQQL(query); err != nil {
+		return []configProblem{{key: key, query: query, err: err}}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/Fabian-G/quest/di"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConfigCheckSucceedsForValidConfig(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	cfg.DefaultView.Query = "!done"
+	cfg.DefaultView.Sort = []string{"+priority"}
+	cfg.DefaultView.Projection = []string{"line", "description"}
+
+	out, err := runWithOutput(t, cfg, "config", "check")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "No problems found")
+}
+
+func Test_ConfigCheckReportsAllProblems(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	cfg.Styles = []di.StyleDef{{If: "done &&", Fg: "1"}}
+	cfg.Holidays = path.Join(path.Dir(cfg.TodoFile), "does-not-exist.txt")
+	cfg.Dependencies.Tag = "after"
+	cfg.Dependencies.OnComplete = "delete"
+	cfg.Subtasks.ParentCompletion = "never"
+	cfg.Functions = []di.FunctionDef{{Name: "script", Script: "def other():\n    return True", ResultType: "bool"}}
+	cfg.Macros = []di.MacroDef{{Name: "broken", Query: "done(arg1)", InTypes: []string{"item"}, ResultType: "bool"}}
+	cfg.Views = map[string]di.ViewDef{
+		"inbox": {
+			Query:      "!done && exists x in items: done(y)",
+			Sort:       []string{"+unknown"},
+			Projection: []string{"nonexistent"},
+		},
+	}
+	cfg.Dashboard.Layout = "grid"
+	cfg.Dashboard.Panes = []string{"inbox", "someday"}
+
+	out, err := runWithOutput(t, cfg, "config", "check")
+	assert.Error(t, err)
+	assert.Contains(t, out, "styles[0].if")
+	assert.Contains(t, out, "holidays-file")
+	assert.Contains(t, out, "dependencies.tag")
+	assert.Contains(t, out, "dependencies.on-complete")
+	assert.Contains(t, out, "subtasks.parent-completion")
+	assert.Contains(t, out, "function[0] (script).script")
+	assert.Contains(t, out, "macro[0] (broken).query")
+	assert.Contains(t, out, "views.inbox.query: validation error: unknown identifier: y at position 33")
+	assert.Contains(t, out, "\t!done && exists x in items: done(y)\n\t                                 ^\n")
+	assert.Contains(t, out, "views.inbox.sort")
+	assert.Contains(t, out, "views.inbox.projection")
+	assert.Contains(t, out, "dashboard.layout: unknown layout grid")
+	assert.Contains(t, out, "dashboard.panes: unknown view someday")
+	assert.NotContains(t, out, "unknown view inbox")
+}
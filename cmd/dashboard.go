@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	"github.com/spf13/cobra"
+)
+
+// defaultViewName refers to the default view in the list of dashboard panes
+const defaultViewName = "default"
+
+type dashboardCommand struct {
+	layout string
+}
+
+func newDashboardCommand() *dashboardCommand {
+	cmd := dashboardCommand{}
+
+	return &cmd
+}
+
+func (d *dashboardCommand) command() *cobra.Command {
+	var dashboardCommand = &cobra.Command{
+		Use:   "dashboard",
+		Short: "Shows several views at once",
+		Long: `Dashboard shows the views configured in dashboard.panes (or all views) at once.
+Every pane is an interactive list with the query, projection, sort order and workspaces of its view.
+Panes always show the todo file, so views with another source can not be part of the dashboard.
+Use tab and shift+tab to switch between the panes. All panes are refreshed together when the todo file changes.`,
+		Example: "quest dashboard --layout columns",
+		GroupID: "global-cmd",
+		Args:    cobra.NoArgs,
+		PreRunE: cmdutil.Steps(d.loadList),
+		RunE:    d.dashboard,
+	}
+	dashboardCommand.Flags().StringVar(&d.layout, "layout", "", fmt.Sprintf("How to arrange the panes. One of %v (default is dashboard.layout)", view.Layouts))
+	return dashboardCommand
+}
+
+// loadList reads the todo files of all workspaces that are shown in at least one of the panes.
+func (d *dashboardCommand) loadList(cmd *cobra.Command, args []string) error {
+	container := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	config := container.Config()
+	// Tasks that belong to no workspace in particular (e.g. spawned occurrences) go to the current one
+	repos := []*todotxt.Repo{container.TodoTxtRepo()}
+	for _, name := range dashboardPanes(config) {
+		def, ok := dashboardView(config, name)
+		if !ok {
+			return fmt.Errorf("unknown view %s in dashboard.panes", name)
+		}
+		union, err := cmdutil.TodoUnion(cmd, def.Workspaces)
+		if err != nil {
+			return fmt.Errorf("view %s: %w", name, err)
+		}
+		for _, r := range union.Repos() {
+			if !slices.Contains(repos, r) {
+				repos = append(repos, r)
+			}
+		}
+	}
+	union := todotxt.NewUnion(repos...)
+	list, err := union.Read()
+	if err != nil {
+		return err
+	}
+	cmd.SetContext(context.WithValue(cmd.Context(), cmdutil.UnionKey, union))
+	cmd.SetContext(context.WithValue(cmd.Context(), cmdutil.ListKey, list))
+	return nil
+}
+
+func (d *dashboardCommand) dashboard(cmd *cobra.Command, args []string) error {
+	container := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	union := cmd.Context().Value(cmdutil.UnionKey).(*todotxt.Union)
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	config := container.Config()
+	layout := config.Dashboard.Layout
+	if d.layout != "" {
+		layout = d.layout
+	}
+	if layout == "" {
+		layout = view.LayoutTabs
+	}
+	if !slices.Contains(view.Layouts, layout) {
+		return fmt.Errorf("unknown layout %s. Must be one of %v", layout, view.Layouts)
+	}
+
+	names := dashboardPanes(config)
+	panes := make([]view.List, 0, len(names))
+	for _, name := range names {
+		def, ok := dashboardView(config, name)
+		if !ok {
+			return fmt.Errorf("unknown view %s in dashboard.panes", name)
+		}
+		pane, err := d.pane(cmd, args, container, name, def, union, list)
+		if err != nil {
+			return fmt.Errorf("view %s: %w", name, err)
+		}
+		panes = append(panes, pane)
+	}
+	return runDashboard(view.NewDashboard(union, layout, names, panes), list)
+}
+
+// runDashboard runs the dashboard until the user quits
+var runDashboard = func(dashboard view.Dashboard, list *todotxt.List) error {
+	return dashboard.Run(list)
+}
+
+// pane builds the interactive list of a single view, just like the view command would.
+// It shows only the tasks of the workspaces of the view and adds new tasks to the first of them.
+func (d *dashboardCommand) pane(cmd *cobra.Command, args []string, container *di.Container, name string, def di.ViewDef, union *todotxt.Union, list *todotxt.List) (view.List, error) {
+	if err := checkPaneSource(def); err != nil {
+		return view.List{}, err
+	}
+	workspaces, err := cmdutil.TodoUnion(cmd, def.Workspaces)
+	if err != nil {
+		return view.List{}, err
+	}
+	repos := workspaces.Repos()
+	query, err := cmdutil.ParseTaskSelection(def.Query, nil, cmdutil.Selection{})
+	if err != nil {
+		return view.List{}, fmt.Errorf("invalid query specified: %w", err)
+	}
+	sortCompiler := container.SortCompiler()
+	sortFunc, err := sortCompiler.CompileSortFunc(def.Sort)
+	if err != nil {
+		return view.List{}, err
+	}
+	projector := container.ViewProjector(name)
+	if err := projector.Verify(def.Projection, list); err != nil {
+		return view.List{}, fmt.Errorf("invalid projection: %w", err)
+	}
+	getTasks := func(l *todotxt.List) []*todotxt.Item {
+		selection := cmdutil.Filter(container.Config(), query, l)
+		selection = slices.DeleteFunc(selection, func(i *todotxt.Item) bool { return !slices.Contains(repos, l.SourceOf(i)) })
+		slices.SortStableFunc(selection, sortFunc)
+		return selection
+	}
+
+	actions := newViewCommand(def, container).actions(cmd, args)
+	add := actions.Add
+	actions.Add = func(l *todotxt.List, description string) (*todotxt.Item, error) {
+		item, err := add(l, description)
+		if err == nil {
+			l.SetSource(repos[0], item)
+		}
+		return item, err
+	}
+	pane := view.NewList(union, projector, def.Projection, getTasks, true).
+		WithLimit(def.Limit).
+		WithSortCompiler(sortCompiler.CompileSortFunc).
+		WithActions(actions)
+	if def.Tree {
+		if container.Config().Subtasks.Tag == "" {
+			return view.List{}, errors.New("the tree view requires subtasks. Configure subtasks.tag to use it")
+		}
+		pane = pane.AsTree(qselect.ParentOf)
+	}
+	return pane, nil
+}
+
+// checkPaneSource rejects views that do not show the todo file, because the panes operate on the tasks of the dashboard
+func checkPaneSource(def di.ViewDef) error {
+	if def.Source != "" && def.Source != di.SourceTodo {
+		return fmt.Errorf("source %s is not supported in the dashboard. Only views of the todo file can be shown", def.Source)
+	}
+	return nil
+}
+
+// dashboardPanes returns the names of the views shown by the dashboard.
+// If none are configured, these are the default view followed by all other views of the todo file.
+func dashboardPanes(config di.Config) []string {
+	if len(config.Dashboard.Panes) > 0 {
+		return config.Dashboard.Panes
+	}
+	names := make([]string, 0, len(config.Views)+1)
+	for name, def := range config.Views {
+		if checkPaneSource(def) == nil {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	if !slices.Contains(names, defaultViewName) {
+		names = slices.Insert(names, 0, defaultViewName)
+	}
+	return names
+}
+
+// dashboardView returns the view with the given name. "default" refers to
+// the default view, unless there is a view with that name.
+func dashboardView(config di.Config, name string) (di.ViewDef, bool) {
+	if def, ok := config.Views[name]; ok {
+		return def, true
+	}
+	return config.DefaultView, name == defaultViewName
+}
@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Fabian-G/quest/cmd"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DashboardRejectsUnknownLayouts(t *testing.T) {
+	cfg := BuildTestConfig(t)
+
+	_, err := runWithOutput(t, cfg, "dashboard", "--layout", "grid")
+	assert.ErrorContains(t, err, "unknown layout grid")
+}
+
+func Test_DashboardRejectsUnknownPanes(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	cfg.Views = map[string]di.ViewDef{"inbox": cfg.DefaultView}
+	cfg.Dashboard.Panes = []string{"default", "inbox", "someday"}
+
+	_, err := runWithOutput(t, cfg, "dashboard")
+	assert.ErrorContains(t, err, "unknown view someday")
+}
+
+func Test_DashboardPanesShowAndChangeOnlyTheirWorkspaces(t *testing.T) {
+	configFile, todoFile, workFile := buildWorkspaceConfig(t)
+	config, err := os.ReadFile(configFile)
+	assert.Nil(t, err)
+	config = append(config, []byte(`
+[views.work]
+workspaces = ["work"]
+
+[dashboard]
+panes = ["default", "work"]
+`)...)
+	assert.Nil(t, os.WriteFile(configFile, config, 0644))
+	var rendered string
+	cmd.SetDashboardRunner(t.Cleanup, func(d view.Dashboard, list *todotxt.List) error {
+		var model tea.Model = d
+		model, _ = model.Update(view.RefreshListMsg{List: list})
+		model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		rendered = model.View()
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("new work task")})
+		model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return nil
+	})
+
+	assert.Nil(t, runWithConfigFile(t, configFile, "dashboard"))
+
+	assert.Contains(t, rendered, "default (1)")
+	assert.Contains(t, rendered, "work (1)")
+	assert.Contains(t, rendered, "work task")
+	assert.NotContains(t, rendered, "private task")
+	assert.Equal(t, []string{"private task"}, ReadLines(t, todoFile))
+	lines := ReadLines(t, workFile)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "(A) work task", lines[0])
+	assert.Contains(t, lines[1], "new work task")
+}
+
+func Test_DashboardRejectsPanesOfTheArchive(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	archive := cfg.DefaultView
+	archive.Source = di.SourceDone
+	cfg.Views = map[string]di.ViewDef{"archive": archive}
+	cfg.Dashboard.Panes = []string{"default", "archive"}
+
+	_, err := runWithOutput(t, cfg, "dashboard")
+	assert.ErrorContains(t, err, "source done is not supported in the dashboard")
+}
@@ -0,0 +1,22 @@
+package cmd_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Fabian-G/quest/di"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CompletingTheLastBlockerPrintsTheUnblockedTask(t *testing.T) {
+	cfg := BuildTestConfig(t, WithIds, func(c di.Config) di.Config {
+		c.Dependencies.Tag = "after"
+		c.Dependencies.OnComplete = "notify"
+		return c
+	})
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("write code id:a\nrelease id:b after:a\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "complete", "--all", "1")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "Task #2 is no longer blocked: release id:b after:a\n")
+}
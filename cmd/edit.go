@@ -11,6 +11,7 @@ import (
 
 	"github.com/Fabian-G/quest/cmd/cmdutil"
 	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/hook"
 	"github.com/Fabian-G/quest/todotxt"
 	"github.com/erikgeiser/promptkit/confirmation"
 	"github.com/spf13/cobra"
@@ -18,10 +19,9 @@ import (
 
 type editCommand struct {
 	viewDef   di.ViewDef
-	qql       []string
-	rng       []string
-	str       []string
+	selection cmdutil.Selection
 	sortOrder []string
+	identity  *hook.Identity
 }
 
 func newEditCommand(def di.ViewDef) *editCommand {
@@ -40,13 +40,14 @@ func (e *editCommand) command() *cobra.Command {
 
 All tasks will receive an internal tag "quest-object-id" (which will not be written to disk though). 
 This tag is used to reliably trigger features like recurrence. So it must not be removed or edited (except when removing the whole task).
+If stable task ids are enabled (see ids.tag), the id tag is used instead. Tasks that do not have an id yet will receive one.
 Other than that you can freely add remove or edit the lines of the file with full support for recurrence, tag expansion and validation.`,
 		GroupID:  "view-cmd",
 		PreRunE:  cmdutil.Steps(cmdutil.LoadList),
 		RunE:     e.edit,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(editCommand, &e.qql, &e.rng, &e.str, nil)
+	cmdutil.RegisterSelectionFlags(editCommand, &e.selection, nil)
 	editCommand.Flags().StringSliceVarP(&e.sortOrder, "sort", "s", e.viewDef.Sort, "The order in which the todo items are loaded into your editor.")
 	return editCommand
 }
@@ -54,8 +55,9 @@ Other than that you can freely add remove or edit the lines of the file with ful
 func (e *editCommand) edit(cmd *cobra.Command, args []string) (err error) {
 	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
 	editor := di.Editor()
+	e.identity = di.Identity()
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
-	selector, err := cmdutil.ParseTaskSelection(e.viewDef.Query, args, e.qql, e.rng, e.str)
+	selector, err := cmdutil.ParseTaskSelection(e.viewDef.Query, args, e.selection)
 	if err != nil {
 		return err
 	}
@@ -96,7 +98,11 @@ func (e *editCommand) edit(cmd *cobra.Command, args []string) (err error) {
 }
 
 func (e *editCommand) dumpDescriptionsToTempFile(list *todotxt.List, items []*todotxt.Item) (file string, err error) {
-	if err := setObjectIdTag(list, items); err != nil {
+	if e.identity != nil {
+		if err := e.identity.Assign(list, items...); err != nil {
+			return "", err
+		}
+	} else if err := setObjectIdTag(list, items); err != nil {
 		return "", err
 	}
 	tmpFile, err := os.CreateTemp("", "quest-edit-*.todo.txt")
@@ -111,8 +117,10 @@ func (e *editCommand) dumpDescriptionsToTempFile(list *todotxt.List, items []*to
 		return "", err
 	}
 
-	if err := clearObjectIdTag(list, items); err != nil {
-		return "", err
+	if e.identity == nil {
+		if err := clearObjectIdTag(list, items); err != nil {
+			return "", err
+		}
 	}
 
 	return tmpFile.Name(), writer.Flush()
@@ -131,7 +139,12 @@ func (e *editCommand) applyChanges(tmpFile string, list *todotxt.List, selection
 		return 0, 0, 0, err
 	}
 
-	changedItems, err := mapToIds(changeList)
+	var changedItems []itemWithId
+	if e.identity != nil {
+		changedItems, err = mapToStableIds(e.identity, changeList, selection)
+	} else {
+		changedItems, err = mapToIds(changeList)
+	}
 	if err != nil {
 		return 0, 0, 0, err
 	}
@@ -234,3 +247,16 @@ func mapToIds(items []*todotxt.Item) ([]itemWithId, error) {
 	}
 	return idMap, nil
 }
+
+func mapToStableIds(identity *hook.Identity, items []*todotxt.Item, selection []*todotxt.Item) ([]itemWithId, error) {
+	idMap := make([]itemWithId, 0) // slice instead of map, because we must retain the order
+	for _, item := range items {
+		stableId := identity.IdOf(item)
+		id := slices.IndexFunc(selection, func(i *todotxt.Item) bool { return stableId != "" && identity.IdOf(i) == stableId })
+		if id != -1 && slices.ContainsFunc(idMap, func(iwi itemWithId) bool { return iwi.id == id }) {
+			return nil, fmt.Errorf("encountered duplicate id %s. Do not copy the %s tag", stableId, identity.Tag)
+		}
+		idMap = append(idMap, itemWithId{id: id, item: item})
+	}
+	return idMap, nil
+}
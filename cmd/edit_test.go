@@ -117,6 +117,30 @@ func Test_TagExpansionIsEvaluatedInOrderOfTheEditFileEvenEvenWhenThereAreNewItem
 	})
 }
 
+func Test_EditUsesStableIdsWhenEnabled(t *testing.T) {
+	di := BuildTestDi(t, BuildTestConfig(t, WithRecurrence, WithIds))
+	todoFile := di.Config().TodoFile
+	todos := `x a done task id:aaaaaa
+A recurring task rec:+1w due:2020-01-01 id:bbbbbb
+Another task`
+	assert.Nil(t, os.WriteFile(todoFile, []byte(todos), 0644))
+
+	di.SetEditor(editSteps(complete(1), shuffle()))
+
+	cmd, ctx := cmd.Root(di)
+	cmd.SetArgs([]string{"edit", "-s", ""})
+	err := cmd.ExecuteContext(ctx)
+
+	assert.Nil(t, err)
+	lines := ReadLines(t, todoFile)
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "x a done task id:aaaaaa", lines[0])
+	assert.Equal(t, "x A recurring task rec:+1w due:2020-01-01 id:bbbbbb", lines[1])
+	assert.Regexp(t, "^Another task id:[0-9a-z]{6}$", lines[2])
+	assert.Regexp(t, "^2022-02-02 A recurring task rec:\\+1w due:2020-01-08 id:[0-9a-z]{6}$", lines[3])
+	assert.NotContains(t, lines[3], "bbbbbb")
+}
+
 func editAttempts(editors ...di.Editor) di.Editor {
 	invocationCount := 0
 	return di.EditorFunc(func(path string) error {
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/spf13/cobra"
+)
+
+type explainCommand struct {
+	viewDef di.ViewDef
+	qql     []string
+}
+
+func newExplainCommand(def di.ViewDef) *explainCommand {
+	cmd := explainCommand{
+		viewDef: def,
+	}
+
+	return &cmd
+}
+
+func (e *explainCommand) command() *cobra.Command {
+	var explainCommand = &cobra.Command{
+		Use:   "explain <line>",
+		Short: "Explains why a task does or does not match the query of the view",
+		Long: `Explain evaluates the query of the view (and all queries given with -q) against the task in the given line.
+It prints every relevant sub-expression together with its value. For quantifiers the binding that decided the result is shown.`,
+		Example: "quest explain 4\nquest inbox explain 2 -q 'due < today'",
+		GroupID: "view-cmd",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: cmdutil.Steps(cmdutil.LoadList),
+		RunE:    e.explain,
+	}
+	explainCommand.Flags().StringArrayVarP(&e.qql, "qql", "q", nil, "Additional QQL query to explain")
+	return explainCommand
+}
+
+func (e *explainCommand) explain(cmd *cobra.Command, args []string) error {
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	line, err := strconv.Atoi(args[0])
+	if err != nil || line < 1 || line > list.Len() {
+		return fmt.Errorf("invalid line %s. Must be a number between 1 and %d", args[0], list.Len())
+	}
+	item := list.GetLine(line)
+	fmt.Fprintf(cmd.OutOrStdout(), "%d: %s\n\n", line, item.String())
+	queries := e.qql
+	if strings.TrimSpace(e.viewDef.Query) != "" {
+		queries = append([]string{e.viewDef.Query}, queries...)
+	}
+	for _, query := range queries {
+		explanation, err := qselect.ExplainQQL(query, list, item)
+		if err != nil {
+			return fmt.Errorf("could not compile query %s: %w", query, err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), explanation.String())
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+)
+
+// SetConflictResolution replaces the interactive conflict resolution of merge-conflicts until the test ends
+func SetConflictResolution(cleanup func(func()), resolve func(string, []todotxt.TaskChange) ([]todotxt.TaskChange, error)) {
+	previous := resolveConflicts
+	resolveConflicts = resolve
+	cleanup(func() { resolveConflicts = previous })
+}
+
+// SetListRunner replaces running the list of the view commands until the test ends
+func SetListRunner(cleanup func(func()), run func(view.List, *todotxt.List) error) {
+	previous := runList
+	runList = run
+	cleanup(func() { runList = previous })
+}
+
+// SetDashboardRunner replaces running the dashboard until the test ends
+func SetDashboardRunner(cleanup func(func()), run func(view.Dashboard, *todotxt.List) error) {
+	previous := runDashboard
+	runDashboard = run
+	cleanup(func() { runDashboard = previous })
+}
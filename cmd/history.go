@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/spf13/cobra"
+)
+
+type historyCommand struct {
+}
+
+func newHistoryCommand() *historyCommand {
+	cmd := historyCommand{}
+
+	return &cmd
+}
+
+func (h *historyCommand) command() *cobra.Command {
+	var historyCommand = &cobra.Command{
+		Use:   "history",
+		Short: "Lists the changes that can be undone or redone",
+		Long: `History lists the recorded changes, the most recent one first.
+Changes marked with "*" are currently applied and can be reverted with "quest undo".
+The remaining ones have been undone and can be reapplied with "quest redo".`,
+		Example: "quest history",
+		GroupID: "global-cmd",
+		Args:    cobra.NoArgs,
+		RunE:    h.history,
+	}
+	return historyCommand
+}
+
+func (h *historyCommand) history(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	entries, applied, err := di.Journal().History()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("no changes recorded")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\t#\tTime\tCommand\tFiles")
+	for i := len(entries) - 1; i >= 0; i-- {
+		marker := ""
+		if i < applied {
+			marker = "*"
+		}
+		files := entries[i].Files()
+		for j := range files {
+			files[j] = path.Base(files[j])
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", marker, i+1, entries[i].Time.Format(time.DateTime), entries[i].Command, strings.Join(files, ", "))
+	}
+	return w.Flush()
+}
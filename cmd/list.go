@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 
 	"github.com/Fabian-G/quest/cmd/cmdutil"
 	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qselect"
 	"github.com/Fabian-G/quest/qsort"
 	"github.com/Fabian-G/quest/todotxt"
 	"github.com/Fabian-G/quest/view"
@@ -13,24 +15,26 @@ import (
 )
 
 type viewCommand struct {
-	def             di.ViewDef
-	projection      []string
-	sortOrder       []string
-	limit           int
-	qqlSearch       []string
-	rngSearch       []string
-	stringSearch    []string
-	json            bool
-	interactive     bool
-	trackingEnabled bool
-	notesEnabled    bool
+	def               di.ViewDef
+	projection        []string
+	sortOrder         []string
+	limit             int
+	selection         cmdutil.Selection
+	json              bool
+	interactive       bool
+	archive           bool
+	tree              bool
+	trackingEnabled   bool
+	notesEnabled      bool
+	recurrenceEnabled bool
 }
 
 func newViewCommand(def di.ViewDef, container *di.Container) *viewCommand {
 	cmd := viewCommand{
-		def:             def,
-		trackingEnabled: len(container.Config().Tracking.Tag) > 0,
-		notesEnabled:    container.NotesRepo() != nil,
+		def:               def,
+		trackingEnabled:   len(container.Config().Tracking.Tag) > 0,
+		notesEnabled:      container.NotesRepo() != nil,
+		recurrenceEnabled: container.Recurrence() != nil,
 	}
 
 	return &cmd
@@ -41,7 +45,7 @@ func (v *viewCommand) command(name string) *cobra.Command {
 		Use:     name + " [selectors...]",
 		Short:   v.def.Description,
 		GroupID: "view",
-		PreRunE: cmdutil.Steps(cmdutil.LoadList),
+		PreRunE: cmdutil.Steps(v.load),
 		RunE:    v.list,
 	}
 	listCmd.AddGroup(&cobra.Group{
@@ -54,7 +58,9 @@ func (v *viewCommand) command(name string) *cobra.Command {
 	listCmd.Flags().IntVarP(&v.limit, "limit", "l", v.def.Limit, "Show only the first l items. Set to -1 to show all items")
 	listCmd.Flags().BoolVar(&v.json, "json", false, "Output the result in json format. This ignores -p")
 	listCmd.Flags().BoolVarP(&v.interactive, "interactive", "i", v.def.Interactive, "set to false to make the list non-interactive")
-	cmdutil.RegisterSelectionFlags(listCmd, &v.qqlSearch, &v.rngSearch, &v.stringSearch, nil)
+	listCmd.Flags().BoolVarP(&v.archive, "archive", "A", false, "Include the archived tasks of the done file")
+	listCmd.Flags().BoolVar(&v.tree, "tree", v.def.Tree, "Show subtasks indented below their parents. Requires subtasks.tag to be set")
+	cmdutil.RegisterSelectionFlags(listCmd, &v.selection, nil)
 
 	listCmd.AddCommand(newAddCommand(v.def).command())
 	listCmd.AddCommand(newCompleteCommand(v.def).command())
@@ -64,21 +70,43 @@ func (v *viewCommand) command(name string) *cobra.Command {
 	listCmd.AddCommand(newArchiveCommand(v.def).command())
 	listCmd.AddCommand(newSetCommand(v.def).command())
 	listCmd.AddCommand(newUnsetCommand(v.def).command())
+	listCmd.AddCommand(newStatsCommand(v.def).command())
+	listCmd.AddCommand(newChartCommand(v.def).command())
+	listCmd.AddCommand(newExplainCommand(v.def).command())
 	if v.notesEnabled {
 		listCmd.AddCommand(newNotesCommand(v.def).command())
 	}
 	if v.trackingEnabled {
 		listCmd.AddCommand(newTrackCommand(v.def).command())
 	}
+	if v.recurrenceEnabled {
+		listCmd.AddCommand(newRecurCommand(v.def).command())
+	}
 
 	return listCmd
 }
 
+// load reads the tasks of the configured source. Only the list itself supports other sources,
+// view commands always operate on the todo file.
+func (v *viewCommand) load(cmd *cobra.Command, args []string) error {
+	return cmdutil.LoadSource(v.source())(cmd, args)
+}
+
+func (v *viewCommand) source() string {
+	switch {
+	case v.archive:
+		return di.SourceBoth
+	case v.def.Source == "":
+		return di.SourceTodo
+	}
+	return v.def.Source
+}
+
 func (v *viewCommand) list(cmd *cobra.Command, args []string) error {
 	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
-	repo := di.TodoTxtRepo()
+	union := cmd.Context().Value(cmdutil.UnionKey).(*todotxt.Union)
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
-	query, err := cmdutil.ParseTaskSelection(v.def.Query, args, v.qqlSearch, v.rngSearch, v.stringSearch)
+	query, err := cmdutil.ParseTaskSelection(v.def.Query, args, v.selection)
 	if err != nil {
 		return fmt.Errorf("invalid query specified: %w", err)
 	}
@@ -87,7 +115,12 @@ func (v *viewCommand) list(cmd *cobra.Command, args []string) error {
 		TagTypes:        di.Config().TagTypes(),
 		ScoreCalculator: di.QuestScoreCalculator(),
 	}
-	sortFunc, err := sortCompiler.CompileSortFunc(v.sortOrder)
+	sortOrder := make([]string, 0, len(v.selection.Fuzzy)+len(v.sortOrder))
+	for _, f := range v.selection.Fuzzy {
+		sortOrder = append(sortOrder, "-fuzzy:"+f) // best matches first
+	}
+	sortOrder = append(sortOrder, v.sortOrder...)
+	sortFunc, err := sortCompiler.CompileSortFunc(sortOrder)
 	if err != nil {
 		return err
 	}
@@ -97,18 +130,86 @@ func (v *viewCommand) list(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid projection: %w", err)
 	}
 
+	// The limit is applied by the list view, because the selection can be filtered further in interactive mode
 	getTasks := func(l *todotxt.List) []*todotxt.Item {
-		selection := query.Filter(l)
+		selection := cmdutil.Filter(di.Config(), query, l)
 		slices.SortStableFunc(selection, sortFunc)
+		return selection
+	}
+
+	if v.json {
+		selection := getTasks(list)
 		if v.limit > 0 {
 			selection = selection[:min(len(selection), v.limit)]
 		}
-		return selection
+		return todotxt.DefaultJsonEncoder.Encode(cmd.OutOrStdout(), list, selection)
 	}
 
-	if v.json {
-		return todotxt.DefaultJsonEncoder.Encode(cmd.OutOrStdout(), list, getTasks(list))
+	listView := view.NewList(union, projector, v.projection, getTasks, v.interactive).
+		WithLimit(v.limit).
+		WithSortCompiler(sortCompiler.CompileSortFunc).
+		WithActions(v.actions(cmd, args))
+	if v.tree {
+		if di.Config().Subtasks.Tag == "" {
+			return errors.New("the tree view requires subtasks. Configure subtasks.tag to use it")
+		}
+		listView = listView.AsTree(qselect.ParentOf)
 	}
+	return runList(listView, list)
+}
+
+// runList shows the list. For interactive lists it runs until the user quits.
+var runList = func(listView view.List, list *todotxt.List) error {
+	return listView.Run(list)
+}
 
-	return view.NewList(repo, projector, v.projection, getTasks, v.interactive).Run(list)
+// actions returns the actions of the interactive list. Every action is saved immediately.
+func (v *viewCommand) actions(cmd *cobra.Command, args []string) view.Actions {
+	container := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	union := cmd.Context().Value(cmdutil.UnionKey).(*todotxt.Union)
+	// Every action is a journal entry of its own, so that undo reverts one action at a time
+	journal := container.Journal()
+	actions := view.Actions{
+		Save: func(l *todotxt.List, action string) error {
+			defer journal.NewInvocation()
+			return cmdutil.SaveUnionAs(cmd, cmdutil.ActionCommand(cmd, args, action), union, l)
+		},
+		Add: func(l *todotxt.List, description string) (*todotxt.Item, error) {
+			return addTask(v.def, l, description, todotxt.PrioNone, container.Config().NowFunc)
+		},
+		Notifications: container.SetNotify,
+	}
+	// Archiving is only possible if the list does not already contain the archive
+	if v.source() == di.SourceTodo {
+		actions.Archive = func(l *todotxt.List, items []*todotxt.Item) error {
+			if err := cmdutil.LoadDoneList(cmd, args); err != nil {
+				return err
+			}
+			doneList := cmd.Context().Value(cmdutil.DoneListKey).(*todotxt.List)
+			doneUnion := cmd.Context().Value(cmdutil.DoneUnionKey).(*todotxt.Union)
+			if err := archiveTasks(l, doneList, doneUnion, items); err != nil {
+				return err
+			}
+			// The done file is saved first, so the entry is named after the action just like the todo file changes that follow
+			action := cmdutil.ActionCommand(cmd, args, fmt.Sprintf("Archived %d task(s)", len(items)))
+			if err := cmdutil.SaveUnionAs(cmd, action, doneUnion, doneList); err != nil {
+				return fmt.Errorf("could not save done file: %w", err)
+			}
+			return doneUnion.Close()
+		}
+	}
+	if v.trackingEnabled {
+		actions.Track = func(l *todotxt.List, item *todotxt.Item) error {
+			return startTracking(container.Config().Tracking.Tag, item)
+		}
+	}
+	if v.notesEnabled {
+		actions.Notes = func(l *todotxt.List, item *todotxt.Item) error {
+			return openNote(container, item, func() error {
+				defer journal.NewInvocation()
+				return cmdutil.SaveUnionAs(cmd, cmdutil.ActionCommand(cmd, args, "Opened note"), union, l)
+			})
+		}
+	}
+	return actions
 }
@@ -0,0 +1,125 @@
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/Fabian-G/quest/cmd"
+	"github.com/Fabian-G/quest/di"
+	"github.com/stretchr/testify/assert"
+)
+
+func runWithOutput(t *testing.T, cfg di.Config, args ...string) (string, error) {
+	cmd, ctx := cmd.Root(BuildTestDi(t, cfg))
+	out := bytes.Buffer{}
+	cmd.SetOut(&out)
+	cmd.SetArgs(args)
+	err := cmd.ExecuteContext(ctx)
+	return out.String(), err
+}
+
+func Test_ListIncludesArchivedTasksOnlyIfRequested(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("an open task\n"), 0644))
+	assert.Nil(t, os.WriteFile(cfg.DoneFile, []byte("x 2022-02-01 an archived task\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "--json")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "an open task")
+	assert.NotContains(t, out, "an archived task")
+
+	out, err = runWithOutput(t, cfg, "--json", "--archive", "-q", "archived")
+	assert.Nil(t, err)
+	assert.NotContains(t, out, "an open task")
+	assert.Contains(t, out, "an archived task")
+}
+
+func Test_ViewSourceSelectsDoneFile(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	cfg.DefaultView.Source = di.SourceDone
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("an open task\n"), 0644))
+	assert.Nil(t, os.WriteFile(cfg.DoneFile, []byte("x 2022-02-01 an archived task\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "--json", "-q", "completion >= ymd(2022, 2, 1)")
+	assert.Nil(t, err)
+	assert.NotContains(t, out, "an open task")
+	assert.Contains(t, out, "an archived task")
+}
+
+func Test_ParallelFilterSelectsTheSameTasks(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("task 1 +a\ntask 2\ntask 3 +a\n"), 0644))
+
+	sequential, err := runWithOutput(t, cfg, "--json", "-q", "+a")
+	assert.Nil(t, err)
+	cfg.Parallel = 2
+	parallel, err := runWithOutput(t, cfg, "--json", "-q", "+a")
+	assert.Nil(t, err)
+
+	assert.Contains(t, sequential, "task 3")
+	assert.Equal(t, sequential, parallel)
+}
+
+func Test_FuzzySearchRanksBestMatchFirst(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("wait for the reporting tool\nsomething else\nwrite report\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "--json", "-z", "wrtrpt")
+	assert.Nil(t, err)
+	assert.NotContains(t, out, "something else")
+	assert.Contains(t, out, "write report")
+	assert.Less(t, strings.Index(out, "write report"), strings.Index(out, "wait for the reporting tool"))
+}
+
+func Test_ExplainPrintsTheValuesOfSubExpressions(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("a task +foo\nanother task +bar\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "explain", "2", "-q", "exists p in projects: p == \"+foo\"")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "2: another task +bar")
+	assert.Contains(t, out, "= false (no element of 1 satisfies the expression)")
+
+	_, err = runWithOutput(t, cfg, "explain", "3")
+	assert.Error(t, err)
+}
+
+func Test_ScriptFunctionsFromTheConfigCanBeUsedInQueries(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	cfg.Functions = []di.FunctionDef{{
+		Name:       "isUrgent",
+		InTypes:    []string{"item"},
+		ResultType: "bool",
+		Script:     "def isUrgent(item):\n    return item.priority == \"A\" or \"urgent\" in item.tags\n",
+	}}
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("(A) first\nsecond urgent:yes\nthird\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "-q", "isUrgent", "--json")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "first")
+	assert.Contains(t, out, "second")
+	assert.NotContains(t, out, "third")
+}
+
+func Test_BusinessDaysSkipTheDaysOfTheHolidaysFile(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	cfg.Holidays = path.Join(path.Dir(cfg.TodoFile), "holidays.txt")
+	assert.Nil(t, os.WriteFile(cfg.Holidays, []byte("# Some holiday\n2022-02-03\n"), 0644))
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("first due:2022-02-03\nsecond due:2022-02-04\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "-q", `date(tag(it, "due")) == date("2022-02-02") + 1bd`, "--json")
+	assert.Nil(t, err)
+	assert.NotContains(t, out, "first")
+	assert.Contains(t, out, "second")
+}
+
+func Test_TreeViewRequiresSubtasks(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("a task\n"), 0644))
+
+	_, err := runWithOutput(t, cfg, "--tree")
+	assert.ErrorContains(t, err, "subtasks.tag")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	"github.com/spf13/cobra"
+)
+
+// resolveConflicts lets the user choose the changes of the conflict file that should be applied
+var resolveConflicts = func(conflictFile string, changes []todotxt.TaskChange) ([]todotxt.TaskChange, error) {
+	return view.NewConflictResolution(conflictFile, changes).Run()
+}
+
+type mergeConflictsCommand struct {
+	resolved []string
+}
+
+func newMergeConflictsCommand() *mergeConflictsCommand {
+	cmd := mergeConflictsCommand{}
+
+	return &cmd
+}
+
+func (m *mergeConflictsCommand) command() *cobra.Command {
+	var mergeConflictsCommand = &cobra.Command{
+		Use:   "merge-conflicts",
+		Short: "Interactively merges sync conflict files into the todo file",
+		Long: `Merge-conflicts compares every conflict file (created by Syncthing or quest itself) with your todo.txt.
+For each added, removed or modified task you can choose whether to keep the version of the todo file
+or to take the version of the conflict file. Afterwards the merged list is saved and the conflict files are deleted.`,
+		Example: "quest merge-conflicts",
+		GroupID: "global-cmd",
+		Args:    cobra.NoArgs,
+		// Skip the sync conflict protection (and the recurrence sync) of the root command. We are here to fix the conflicts after all.
+		PersistentPreRunE: cmdutil.Steps(cmdutil.SetupSteps()...),
+		PreRunE:           cmdutil.Steps(cmdutil.LoadList),
+		RunE:              m.mergeConflicts,
+		PostRunE:          cmdutil.Steps(cmdutil.SaveList, m.removeResolved),
+	}
+	return mergeConflictsCommand
+}
+
+func (m *mergeConflictsCommand) mergeConflicts(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	conflicts, err := cmdutil.SyncConflicts(di.Config().TodoFile)
+	if err != nil {
+		return fmt.Errorf("could not check for sync conflicts: %w", err)
+	}
+	if len(conflicts) == 0 {
+		fmt.Println("There are no sync conflicts.")
+		return nil
+	}
+	for _, conflict := range conflicts {
+		theirs, err := readConflictFile(conflict)
+		if err != nil {
+			return err
+		}
+		accepted, err := resolveConflicts(conflict, todotxt.DiffTasks(list.Tasks(), theirs))
+		if err != nil {
+			return err
+		}
+		// The tasks of the conflict file have already been processed by the hooks on the other device
+		if err := list.Secret(func() error { return applyTaskChanges(list, accepted) }); err != nil {
+			return err
+		}
+		m.resolved = append(m.resolved, conflict)
+	}
+	return nil
+}
+
+func (m *mergeConflictsCommand) removeResolved(cmd *cobra.Command, args []string) error {
+	for _, file := range m.resolved {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("could not remove conflict file %s: %w", file, err)
+		}
+		fmt.Printf("Merged and removed %s\n", file)
+	}
+	return nil
+}
+
+func readConflictFile(file string) ([]*todotxt.Item, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not open conflict file %s: %w", file, err)
+	}
+	defer f.Close()
+	items, err := todotxt.DefaultDecoder.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode conflict file %s: %w", file, err)
+	}
+	return items, nil
+}
+
+func applyTaskChanges(list *todotxt.List, changes []todotxt.TaskChange) error {
+	for _, c := range changes {
+		var err error
+		switch c.Kind {
+		case todotxt.Modified:
+			err = c.Ours.Apply(c.Theirs)
+		case todotxt.Added:
+			err = list.Add(c.Theirs)
+		case todotxt.Removed:
+			err = list.Remove(list.LineOf(c.Ours))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Fabian-G/quest/cmd"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func conflictFileOf(todoFile string) string {
+	return strings.TrimSuffix(todoFile, ".txt") + ".sync-conflict-20230101-120000.txt"
+}
+
+func Test_MergeConflictsSavesTheMergedListAndRemovesTheConflictFile(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("task 1\ntask 2\n"), 0644))
+	conflict := conflictFileOf(cfg.TodoFile)
+	assert.Nil(t, os.WriteFile(conflict, []byte("task 1\ntask 2\ntask 3\n"), 0644))
+	var resolved string
+	cmd.SetConflictResolution(t.Cleanup, func(file string, changes []todotxt.TaskChange) ([]todotxt.TaskChange, error) {
+		resolved = file
+		return changes, nil
+	})
+
+	assert.ErrorContains(t, run(t, cfg, "--json"), "sync conflict")
+	assert.Nil(t, run(t, cfg, "merge-conflicts"))
+
+	assert.Equal(t, conflict, resolved)
+	assert.Equal(t, []string{"task 1", "task 2", "task 3"}, ReadLines(t, cfg.TodoFile))
+	assert.NoFileExists(t, conflict)
+	assert.Nil(t, run(t, cfg, "--json"))
+}
+
+func Test_MergeConflictsKeepsRejectedChangesOut(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("task 1\ntask 2\n"), 0644))
+	conflict := conflictFileOf(cfg.TodoFile)
+	assert.Nil(t, os.WriteFile(conflict, []byte("task 1\ntask 3\n"), 0644))
+	cmd.SetConflictResolution(t.Cleanup, func(file string, changes []todotxt.TaskChange) ([]todotxt.TaskChange, error) {
+		return nil, nil
+	})
+
+	assert.Nil(t, run(t, cfg, "merge-conflicts"))
+
+	assert.Equal(t, []string{"task 1", "task 2"}, ReadLines(t, cfg.TodoFile))
+	assert.NoFileExists(t, conflict)
+}
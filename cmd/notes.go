@@ -12,10 +12,8 @@ import (
 )
 
 type notesCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
 }
 
 func newNotesCommand(def di.ViewDef) *notesCommand {
@@ -35,7 +33,7 @@ func (n *notesCommand) command() *cobra.Command {
 		RunE:    n.notes,
 		// No PostRun needed, because we handle saving manually here
 	}
-	cmdutil.RegisterSelectionFlags(notesCommand, &n.qql, &n.rng, &n.str, nil)
+	cmdutil.RegisterSelectionFlags(notesCommand, &n.selection, nil)
 
 	var cleanCommand = &cobra.Command{
 		Use:     "clean",
@@ -49,11 +47,9 @@ func (n *notesCommand) command() *cobra.Command {
 
 func (n *notesCommand) notes(cmd *cobra.Command, args []string) (err error) {
 	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
-	repo := di.TodoTxtRepo()
-	editor := di.Editor()
-	notesRepo := di.NotesRepo()
+	union := cmd.Context().Value(cmdutil.UnionKey).(*todotxt.Union)
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
-	selector, err := cmdutil.ParseTaskSelection(n.viewDef.Query, args, n.qql, n.rng, n.str)
+	selector, err := cmdutil.ParseTaskSelection(n.viewDef.Query, args, n.selection)
 	if err != nil {
 		return err
 	}
@@ -71,16 +67,21 @@ func (n *notesCommand) notes(cmd *cobra.Command, args []string) (err error) {
 		selectedTask = selection
 	}
 
-	note, err := notesRepo.Get(selectedTask)
+	return openNote(di, selectedTask, func() error { return union.Save(list) })
+}
+
+// openNote opens (or creates) the note of item. Save is called before the editor is opened.
+func openNote(di *di.Container, item *todotxt.Item, save func() error) error {
+	note, err := di.NotesRepo().Get(item)
 	if err != nil {
 		return fmt.Errorf("could not get note for selected task: %w", err)
 	}
 
 	// Save the list before running the editor, because we expect the user to spent a long time in there
-	if err = repo.Save(list); err != nil {
+	if err = save(); err != nil {
 		return err
 	}
-	return editor.Edit(note)
+	return di.Editor().Edit(note)
 }
 
 func (n *notesCommand) clean(cmd *cobra.Command, args []string) (err error) {
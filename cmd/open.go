@@ -7,6 +7,7 @@ import (
 
 	"github.com/Fabian-G/quest/cmd/cmdutil"
 	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/todotxt"
 	"github.com/erikgeiser/promptkit/confirmation"
 	"github.com/spf13/cobra"
 )
@@ -41,11 +42,17 @@ func (o *openCommand) open(cmd *cobra.Command, args []string) (err error) {
 	repo := di.TodoTxtRepo()
 	editor := di.Editor()
 
+	return cmdutil.RecordChanges(cmd, args, cfg.TodoFile, func() error {
+		return o.editUntilValid(cfg.TodoFile, repo, editor)
+	})
+}
+
+func (o *openCommand) editUntilValid(file string, repo *todotxt.Repo, editor di.Editor) (err error) {
 	for {
-		if err := editor.Edit(cfg.TodoFile); err != nil {
+		if err := editor.Edit(file); err != nil {
 			return err
 		}
-		todoFile, err := os.Open(cfg.TodoFile)
+		todoFile, err := os.Open(file)
 		if err != nil {
 			return err
 		}
@@ -12,11 +12,9 @@ import (
 )
 
 type prioritizeCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
-	all     bool
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	all       bool
 }
 
 func newPrioritizeCommand(def di.ViewDef) *prioritizeCommand {
@@ -40,7 +38,7 @@ func (p *prioritizeCommand) command() *cobra.Command {
 		RunE:     p.prioritize,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(prioritizeCommand, &p.qql, &p.rng, &p.str, &p.all)
+	cmdutil.RegisterSelectionFlags(prioritizeCommand, &p.selection, &p.all)
 	return prioritizeCommand
 }
 
@@ -50,7 +48,7 @@ func (p *prioritizeCommand) prioritize(cmd *cobra.Command, args []string) error
 	if err != nil {
 		return fmt.Errorf("invalid priority %s: %w", args[0], err)
 	}
-	selector, err := cmdutil.ParseTaskSelection(p.viewDef.Query, args[1:], p.qql, p.rng, p.str)
+	selector, err := cmdutil.ParseTaskSelection(p.viewDef.Query, args[1:], p.selection)
 	if err != nil {
 		return err
 	}
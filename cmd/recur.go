@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	"github.com/spf13/cobra"
+)
+
+type recurCommand struct {
+	viewDef   di.ViewDef
+	horizon   string
+	selection cmdutil.Selection
+	all       bool
+}
+
+func newRecurCommand(def di.ViewDef) *recurCommand {
+	cmd := recurCommand{
+		viewDef: def,
+	}
+
+	return &cmd
+}
+
+func (r *recurCommand) command() *cobra.Command {
+	var recurCommand = &cobra.Command{
+		Use:     "recur",
+		Short:   "Commands for working with recurrent tasks",
+		GroupID: "view-cmd",
+	}
+	var syncCommand = &cobra.Command{
+		Use:   "sync",
+		Short: "Spawns all upcoming or missed occurrences of recurrent tasks up to the horizon",
+		Long: `Sync spawns the occurrences of all absolute recurrent tasks (e.g. rec:+1d) whose date is before or on today + horizon,
+even if the previous occurrence has not been completed yet. Occurrences are linked by the series tag, so they are never spawned twice.`,
+		Args:     cobra.NoArgs,
+		PreRunE:  cmdutil.Steps(cmdutil.LoadList),
+		RunE:     r.sync,
+		PostRunE: cmdutil.Steps(cmdutil.SaveList),
+	}
+	syncCommand.Flags().StringVar(&r.horizon, "horizon", "", "How far into the future occurrences should be spawned (e.g. 7d). Defaults to the configured horizon")
+	var skipCommand = &cobra.Command{
+		Use:      "skip [selectors...]",
+		Short:    "Removes the matching recurrent tasks without completing them and spawns their next occurrence",
+		PreRunE:  cmdutil.Steps(cmdutil.LoadList),
+		RunE:     r.skip,
+		PostRunE: cmdutil.Steps(cmdutil.SaveList),
+	}
+	cmdutil.RegisterSelectionFlags(skipCommand, &r.selection, &r.all)
+	recurCommand.AddCommand(syncCommand, skipCommand)
+	return recurCommand
+}
+
+func (r *recurCommand) sync(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	horizon := r.horizon
+	if horizon == "" {
+		horizon = di.Config().Recurrence.Horizon
+	}
+	spawned, err := cmdutil.SyncRecurrence(di, list, horizon)
+	if err != nil {
+		return err
+	}
+	if len(spawned) == 0 {
+		fmt.Println("All occurrences are up to date")
+		return nil
+	}
+	view.NewSuccessMessage("Spawned", list, spawned).Run()
+	return nil
+}
+
+func (r *recurCommand) skip(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	selector, err := cmdutil.ParseTaskSelection(r.viewDef.Query, args, r.selection)
+	if err != nil {
+		return err
+	}
+	recurrent := func(l *todotxt.List, i *todotxt.Item) bool {
+		return len(i.Tags()[di.Config().Recurrence.RecTag]) > 0
+	}
+	selection := qselect.And(notDoneFunc, recurrent, selector).Filter(list)
+	var confirmedSelection []*todotxt.Item = selection
+	if !r.all {
+		confirmedSelection, err = view.NewSelection(selection).Run()
+		if err != nil {
+			return err
+		}
+	}
+	if len(confirmedSelection) == 0 {
+		fmt.Print("no matches")
+		return nil
+	}
+
+	for _, t := range confirmedSelection {
+		if err := di.Recurrence().Skip(list, t); err != nil {
+			return err
+		}
+	}
+	view.NewSuccessMessage("Skipped", list, confirmedSelection).Run()
+	return nil
+}
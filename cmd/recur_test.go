@@ -0,0 +1,64 @@
+package cmd_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RecurSyncSpawnsMissedOccurrences(t *testing.T) {
+	cfg := BuildTestConfig(t, WithRecurrence)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("chore rec:+1d due:2022-01-31 series:abc\n"), 0644))
+
+	_, err := runWithOutput(t, cfg, "recur", "sync")
+	assert.Nil(t, err)
+
+	lines := ReadLines(t, cfg.TodoFile)
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "due:2022-02-01")
+	assert.Contains(t, lines[2], "due:2022-02-02")
+
+	_, err = runWithOutput(t, cfg, "recur", "sync", "--horizon", "2d")
+	assert.Nil(t, err)
+	assert.Len(t, ReadLines(t, cfg.TodoFile), 5)
+}
+
+func Test_AutoSyncSpawnsOccurrencesBeforeEveryCommand(t *testing.T) {
+	cfg := BuildTestConfig(t, WithRecurrence)
+	cfg.Recurrence.AutoSync = true
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("chore rec:+1d due:2022-02-01\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "--json")
+	assert.Nil(t, err)
+	assert.Contains(t, out, "2022-02-02")
+	assert.Len(t, ReadLines(t, cfg.TodoFile), 2)
+}
+
+func Test_UndoKeepsTheTasksSpawnedByAutoSync(t *testing.T) {
+	cfg := BuildTestConfig(t, WithRecurrence, WithHistory)
+	cfg.Recurrence.AutoSync = true
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("chore rec:+1d due:2022-02-01\nanother task\n"), 0644))
+
+	assert.Nil(t, run(t, cfg, "complete", "another task"))
+	assert.Nil(t, run(t, cfg, "undo"))
+
+	lines := ReadLines(t, cfg.TodoFile)
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines, "another task")
+	assert.Contains(t, lines[2], "due:2022-02-02")
+
+	assert.Nil(t, run(t, cfg, "undo"))
+	assert.Equal(t, []string{"chore rec:+1d due:2022-02-01", "another task"}, ReadLines(t, cfg.TodoFile))
+}
+
+func Test_RecurSkipSpawnsTheNextOccurrence(t *testing.T) {
+	cfg := BuildTestConfig(t, WithRecurrence)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("chore rec:+1w due:2022-02-01\nnot recurrent\n"), 0644))
+
+	_, err := runWithOutput(t, cfg, "recur", "skip", "--all")
+	assert.Nil(t, err)
+
+	lines := ReadLines(t, cfg.TodoFile)
+	assert.ElementsMatch(t, []string{"not recurrent", "2022-02-02 chore rec:+1w due:2022-02-08"}, lines)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/spf13/cobra"
+)
+
+type redoCommand struct {
+}
+
+func newRedoCommand() *redoCommand {
+	cmd := redoCommand{}
+
+	return &cmd
+}
+
+func (r *redoCommand) command() *cobra.Command {
+	var redoCommand = &cobra.Command{
+		Use:     "redo",
+		Short:   "Reapplies the last change that was reverted by undo",
+		Example: "quest redo",
+		GroupID: "global-cmd",
+		Args:    cobra.NoArgs,
+		RunE:    r.redo,
+	}
+	return redoCommand
+}
+
+func (r *redoCommand) redo(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	if di.Config().History <= 0 {
+		return errors.New("the history is disabled. Set the history option to enable it")
+	}
+	entry, err := di.Journal().Redo(applyJournalEntry(di))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reapplied \"%s\" (%s)\n", entry.Command, strings.Join(entry.Files(), ", "))
+	return nil
+}
@@ -11,11 +11,9 @@ import (
 )
 
 type removeCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
-	all     bool
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	all       bool
 }
 
 func newRemoveCommand(def di.ViewDef) *removeCommand {
@@ -35,13 +33,13 @@ func (r *removeCommand) command() *cobra.Command {
 		RunE:     r.remove,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(removeCommand, &r.qql, &r.rng, &r.str, &r.all)
+	cmdutil.RegisterSelectionFlags(removeCommand, &r.selection, &r.all)
 	return removeCommand
 }
 
 func (r *removeCommand) remove(cmd *cobra.Command, args []string) error {
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
-	selector, err := cmdutil.ParseTaskSelection(r.viewDef.Query, args, r.qql, r.rng, r.str)
+	selector, err := cmdutil.ParseTaskSelection(r.viewDef.Query, args, r.selection)
 	if err != nil {
 		return err
 	}
@@ -14,14 +14,11 @@ func Root(di *di.Container) (*cobra.Command, context.Context) {
 	rootCmd.Use = "quest [view] [command]"
 	rootCmd.PersistentFlags().String("config", "", "the config file to use") // This is just for the help message. Parsing happens in main.go
 	rootCmd.PersistentFlags().StringP("file", "f", "", "the todo.txt file")
-	rootCmd.PersistentPreRunE = cmdutil.Steps(
-		cmdutil.ConfigOverrides,
-		cmdutil.EnsureTodoFileExits,
-		cmdutil.EnsureDoneFileExists,
-		cmdutil.EnsureNotesDirExists,
-		cmdutil.RegisterMacros,
+	rootCmd.PersistentFlags().StringP("workspace", "W", "", "the workspace to use")
+	rootCmd.PersistentPreRunE = cmdutil.Steps(append(cmdutil.SetupSteps(),
 		cmdutil.SyncConflictProtection,
-	)
+		cmdutil.AutoSyncRecurrence,
+	)...)
 	rootCmd.SilenceUsage = true
 
 	rootCmd.AddGroup(&cobra.Group{
@@ -36,6 +33,12 @@ func Root(di *di.Container) (*cobra.Command, context.Context) {
 	rootCmd.AddCommand(newOpenCommand().command())
 	rootCmd.AddCommand(newVersionCommand().command())
 	rootCmd.AddCommand(newInitCommand().command())
+	rootCmd.AddCommand(newUndoCommand().command())
+	rootCmd.AddCommand(newRedoCommand().command())
+	rootCmd.AddCommand(newHistoryCommand().command())
+	rootCmd.AddCommand(newMergeConflictsCommand().command())
+	rootCmd.AddCommand(newConfigCommand().command())
+	rootCmd.AddCommand(newDashboardCommand().command())
 	for name, def := range di.Config().Views {
 		viewCommand := newViewCommand(def, di)
 		rootCmd.AddCommand(viewCommand.command(name))
@@ -18,11 +18,9 @@ var setProjectRegex = regexp.MustCompile(`^\+[^[:space:]]+$`)
 var setContextRegex = regexp.MustCompile("^@[^[:space:]]+$")
 
 type setCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
-	all     bool
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	all       bool
 }
 
 func newSetCommand(def di.ViewDef) *setCommand {
@@ -44,7 +42,7 @@ func (s *setCommand) command() *cobra.Command {
 		RunE:     s.set,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(setCommand, &s.qql, &s.rng, &s.str, &s.all)
+	cmdutil.RegisterSelectionFlags(setCommand, &s.selection, &s.all)
 	return setCommand
 }
 
@@ -52,7 +50,7 @@ func (s *setCommand) set(cmd *cobra.Command, args []string) error {
 	projectsOps, contextOps, tagOps, selectors := s.parseArgs(args)
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
 
-	selector, err := cmdutil.ParseTaskSelection(s.viewDef.Query, selectors, s.qql, s.rng, s.str)
+	selector, err := cmdutil.ParseTaskSelection(s.viewDef.Query, selectors, s.selection)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qduration"
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	"github.com/spf13/cobra"
+)
+
+type statsCommand struct {
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	period    string
+	since     string
+	tags      []string
+	json      bool
+}
+
+func newStatsCommand(def di.ViewDef) *statsCommand {
+	cmd := statsCommand{
+		viewDef: def,
+	}
+
+	return &cmd
+}
+
+func (s *statsCommand) command() *cobra.Command {
+	var statsCommand = &cobra.Command{
+		Use:   "stats [selectors...]",
+		Short: "Shows statistics about the matching tasks of todo.txt and done.txt",
+		Long: `Stats aggregates over all matching tasks of your todo.txt and done.txt.
+It shows the number of created and completed tasks per period, the average lead time
+(time between creation and completion) and a breakdown by project, context and the given tags.`,
+		Example: "quest stats --period day --since 2w --tag due",
+		GroupID: "view-cmd",
+		PreRunE: cmdutil.Steps(cmdutil.LoadSource(di.SourceBoth)),
+		RunE:    s.stats,
+	}
+	statsCommand.Flags().StringVar(&s.period, "period", "week", "The period of the timeline. One of day, week or month")
+	statsCommand.Flags().StringVar(&s.since, "since", "12w", "How far the timeline should reach into the past")
+	statsCommand.Flags().StringSliceVarP(&s.tags, "tag", "t", nil, "Tags to show a breakdown for")
+	statsCommand.Flags().BoolVar(&s.json, "json", false, "Output the result in json format")
+	cmdutil.RegisterSelectionFlags(statsCommand, &s.selection, nil)
+	return statsCommand
+}
+
+func (s *statsCommand) stats(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
+	selector, err := cmdutil.ParseTaskSelection(s.viewDef.Query, args, s.selection)
+	if err != nil {
+		return err
+	}
+	period, err := qstats.ParsePeriod(s.period)
+	if err != nil {
+		return err
+	}
+	since, err := qduration.Parse(s.since)
+	if err != nil {
+		return fmt.Errorf("invalid duration for --since: %w", err)
+	}
+
+	now := di.Config().NowFunc
+	calc := qstats.Calculator{
+		Period:  period,
+		Begin:   since.SubFrom(now()),
+		Tags:    s.tags,
+		NowFunc: now,
+	}
+	report := calc.Report(selector.Filter(list))
+	if s.json {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	view.NewStatistics(report).Run()
+	return nil
+}
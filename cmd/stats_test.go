@@ -0,0 +1,32 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StatsAggregateOverTodoAndDoneFile(t *testing.T) {
+	cfg := BuildTestConfig(t)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("2022-01-30 an open task +quest\nx 2022-02-01 2022-01-31 done but not archived +quest\n"), 0644))
+	assert.Nil(t, os.WriteFile(cfg.DoneFile, []byte("x 2022-01-29 2022-01-25 an archived task +other\n"), 0644))
+
+	out, err := runWithOutput(t, cfg, "stats", "--json", "--since", "1w")
+	assert.Nil(t, err)
+	report := qstats.Report{}
+	assert.Nil(t, json.Unmarshal([]byte(out), &report))
+
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Open)
+	assert.Equal(t, 2, report.Done)
+	assert.InDelta(t, 2.5, report.LeadTimeDays, 0.001)
+	assert.Len(t, report.Timeline, 2)
+	assert.Equal(t, 2, report.Timeline[0].Created)
+	assert.Equal(t, 1, report.Timeline[0].Completed)
+	assert.Equal(t, 1, report.Timeline[1].Created)
+	assert.Equal(t, 1, report.Timeline[1].Completed)
+	assert.Equal(t, "+quest", report.Projects[0].Key)
+}
@@ -15,10 +15,8 @@ import (
 )
 
 type trackCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
 }
 
 func newTrackCommand(def di.ViewDef) *trackCommand {
@@ -48,7 +46,7 @@ To stop tracking a task you can either remove the tracking tag from the active t
 		RunE:     t.track,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(trackCommand, &t.qql, &t.rng, &t.str, nil)
+	cmdutil.RegisterSelectionFlags(trackCommand, &t.selection, nil)
 	return trackCommand
 }
 
@@ -56,7 +54,7 @@ func (t *trackCommand) track(cmd *cobra.Command, args []string) error {
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
 	tag := cmd.Context().Value(cmdutil.DiKey).(*di.Container).Config().Tracking.Tag
 
-	selector, err := cmdutil.ParseTaskSelection(t.viewDef.Query, args, t.qql, t.rng, t.str)
+	selector, err := cmdutil.ParseTaskSelection(t.viewDef.Query, args, t.selection)
 	if err != nil {
 		return err
 	}
@@ -75,10 +73,14 @@ func (t *trackCommand) track(cmd *cobra.Command, args []string) error {
 		selectedTask = t
 	}
 
-	// We just set the tracking tag here. The tracking hook will do the actual work
-	if err := selectedTask.SetTag(tag, strconv.FormatInt(time.Now().Unix()/60, 10)); err != nil {
+	if err := startTracking(tag, selectedTask); err != nil {
 		return err
 	}
 	view.NewSuccessMessage("Started tracking", list, []*todotxt.Item{selectedTask}).Run()
 	return nil
 }
+
+func startTracking(tag string, item *todotxt.Item) error {
+	// We just set the tracking tag here. The tracking hook will do the actual work
+	return item.SetTag(tag, strconv.FormatInt(time.Now().Unix()/60, 10))
+}
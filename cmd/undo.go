@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Fabian-G/quest/cmd/cmdutil"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qjournal"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/spf13/cobra"
+)
+
+type undoCommand struct {
+}
+
+func newUndoCommand() *undoCommand {
+	cmd := undoCommand{}
+
+	return &cmd
+}
+
+func (u *undoCommand) command() *cobra.Command {
+	var undoCommand = &cobra.Command{
+		Use:   "undo",
+		Short: "Reverts the last change",
+		Long: `Undo reverts the changes of the last command that modified your todo.txt or done.txt.
+Changes to both files (e.g. by the archive command) are reverted together.
+Use "quest history" to see which changes can be undone.`,
+		Example: "quest undo",
+		GroupID: "global-cmd",
+		Args:    cobra.NoArgs,
+		RunE:    u.undo,
+	}
+	return undoCommand
+}
+
+func (u *undoCommand) undo(cmd *cobra.Command, args []string) error {
+	di := cmd.Context().Value(cmdutil.DiKey).(*di.Container)
+	if di.Config().History <= 0 {
+		return errors.New("the history is disabled. Set the history option to enable it")
+	}
+	entry, err := di.Journal().Undo(applyJournalEntry(di))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reverted \"%s\" (%s)\n", entry.Command, strings.Join(entry.Files(), ", "))
+	return nil
+}
+
+// applyJournalEntry returns a function that writes the changes of a journal entry to disk.
+// Either all files are written or none (unless writing itself fails)
+func applyJournalEntry(di *di.Container) func(qjournal.Entry) error {
+	return func(entry qjournal.Entry) error {
+		contents := make(map[string][]string)
+		for _, change := range entry.Changes {
+			lines, ok := contents[change.File]
+			if !ok {
+				var err error
+				if lines, err = cmdutil.ReadLines(change.File); err != nil {
+					return err
+				}
+			}
+			lines, err := qjournal.Patch(lines, change.Hunks)
+			if err != nil {
+				return fmt.Errorf("could not apply changes to %s: %w", change.File, err)
+			}
+			contents[change.File] = lines
+		}
+		lists := make(map[string]*todotxt.List)
+		for file, lines := range contents {
+			items, err := todotxt.DefaultDecoder.Decode(strings.NewReader(strings.Join(lines, "\n")))
+			if err != nil {
+				return fmt.Errorf("could not apply changes to %s: %w", file, err)
+			}
+			lists[file] = todotxt.ListOf(items...)
+		}
+		for file, list := range lists {
+			repo := todotxt.NewRepo(file)
+			repo.Keep = di.Config().KeepBackups
+			if err := repo.Save(list); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Fabian-G/quest/cmd"
+	"github.com/Fabian-G/quest/di"
+	"github.com/Fabian-G/quest/qjournal"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/Fabian-G/quest/view"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func WithHistory(cfg di.Config) di.Config {
+	cfg.History = 10
+	return cfg
+}
+
+func run(t *testing.T, cfg di.Config, args ...string) error {
+	cmd, ctx := cmd.Root(BuildTestDi(t, cfg))
+	cmd.SetArgs(args)
+	return cmd.ExecuteContext(ctx)
+}
+
+func Test_UndoRevertsTodoAndDoneFileTogether(t *testing.T) {
+	cfg := BuildTestConfig(t, WithHistory)
+	todos := `x a done task
+an open task
+x another done task`
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte(todos), 0644))
+
+	assert.Nil(t, run(t, cfg, "archive", "-a"))
+	assert.Equal(t, []string{"an open task"}, ReadLines(t, cfg.TodoFile))
+	assert.Equal(t, []string{"x a done task", "x another done task"}, ReadLines(t, cfg.DoneFile))
+
+	assert.Nil(t, run(t, cfg, "undo"))
+	assert.Equal(t, []string{"x a done task", "an open task", "x another done task"}, ReadLines(t, cfg.TodoFile))
+	assert.Equal(t, []string{""}, ReadLines(t, cfg.DoneFile))
+
+	assert.Nil(t, run(t, cfg, "redo"))
+	assert.Equal(t, []string{"an open task"}, ReadLines(t, cfg.TodoFile))
+	assert.Equal(t, []string{"x a done task", "x another done task"}, ReadLines(t, cfg.DoneFile))
+}
+
+func Test_UndoKeepsUnrelatedChanges(t *testing.T) {
+	cfg := BuildTestConfig(t, WithHistory)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("task 1\ntask 2\n"), 0644))
+
+	assert.Nil(t, run(t, cfg, "complete", "-a", "2"))
+	lines := append([]string{"task 0"}, ReadLines(t, cfg.TodoFile)...)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte(strings.Join(lines, "\n")), 0644))
+	assert.Nil(t, run(t, cfg, "undo"))
+
+	assert.Equal(t, []string{"task 0", "task 1", "task 2"}, ReadLines(t, cfg.TodoFile))
+}
+
+func Test_UndoRevertsOneActionOfTheInteractiveListAtATime(t *testing.T) {
+	cfg := BuildTestConfig(t, WithHistory)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("task 1\n"), 0644))
+	cmd.SetListRunner(t.Cleanup, func(l view.List, list *todotxt.List) error {
+		var model tea.Model = l
+		model, _ = model.Update(view.RefreshListMsg{List: list})
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		return nil
+	})
+
+	assert.Nil(t, run(t, cfg, "--interactive", "--projection", "line,description"))
+	entries, _, err := qjournal.NewJournal(qjournal.Location(cfg.TodoFile), cfg.History).History()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.True(t, strings.HasSuffix(entries[0].Command, ": Toggled 1 task(s)"))
+
+	assert.Nil(t, run(t, cfg, "undo"))
+	assert.True(t, strings.HasPrefix(ReadLines(t, cfg.TodoFile)[0], "x "))
+	assert.Nil(t, run(t, cfg, "undo"))
+	assert.Equal(t, []string{"task 1"}, ReadLines(t, cfg.TodoFile))
+}
+
+func Test_UndoFailsIfThereIsNothingToUndo(t *testing.T) {
+	cfg := BuildTestConfig(t, WithHistory)
+	assert.Nil(t, os.WriteFile(cfg.TodoFile, []byte("task 1\n"), 0644))
+
+	assert.Nil(t, run(t, cfg, "complete", "-a", "1"))
+	assert.Nil(t, run(t, cfg, "undo"))
+	assert.Error(t, run(t, cfg, "undo"))
+}
@@ -16,11 +16,9 @@ import (
 var unsetTagRegex = regexp.MustCompile("^[^[:space:]]+$")
 
 type unsetCommand struct {
-	viewDef di.ViewDef
-	qql     []string
-	rng     []string
-	str     []string
-	all     bool
+	viewDef   di.ViewDef
+	selection cmdutil.Selection
+	all       bool
 }
 
 func newUnsetCommand(def di.ViewDef) *unsetCommand {
@@ -42,7 +40,7 @@ func (u *unsetCommand) command() *cobra.Command {
 		RunE:     u.unset,
 		PostRunE: cmdutil.Steps(cmdutil.SaveList),
 	}
-	cmdutil.RegisterSelectionFlags(unsetCommand, &u.qql, &u.rng, &u.str, &u.all)
+	cmdutil.RegisterSelectionFlags(unsetCommand, &u.selection, &u.all)
 	return unsetCommand
 }
 
@@ -50,7 +48,7 @@ func (u *unsetCommand) unset(cmd *cobra.Command, args []string) error {
 	projectsOps, contextOps, tagOps, selectors := u.parseArgs(args)
 	list := cmd.Context().Value(cmdutil.ListKey).(*todotxt.List)
 
-	selector, err := cmdutil.ParseTaskSelection(u.viewDef.Query, selectors, u.qql, u.rng, u.str)
+	selector, err := cmdutil.ParseTaskSelection(u.viewDef.Query, selectors, u.selection)
 	if err != nil {
 		return err
 	}
@@ -58,6 +58,14 @@ func WithRecurrence(cfg di.Config) di.Config {
 	cfg.Recurrence.RecTag = "rec"
 	cfg.Recurrence.DueTag = "due"
 	cfg.Recurrence.ThresholdTag = "t"
+	cfg.Recurrence.SeriesTag = "series"
+	cfg.Recurrence.Horizon = "0d"
+	return cfg
+}
+
+func WithIds(cfg di.Config) di.Config {
+	cfg.Ids.Tag = "id"
+	cfg.Ids.Length = 6
 	return cfg
 }
 
@@ -0,0 +1,70 @@
+package cmd_test
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Fabian-G/quest/cmd"
+	"github.com/Fabian-G/quest/di"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildWorkspaceConfig(t *testing.T) (string, string, string) {
+	dir := t.TempDir()
+	todoFile := path.Join(dir, "todo.txt")
+	workFile := path.Join(dir, "work", "todo.txt")
+	assert.Nil(t, os.Mkdir(path.Join(dir, "work"), 0777))
+	assert.Nil(t, os.WriteFile(todoFile, []byte("private task\n"), 0644))
+	assert.Nil(t, os.WriteFile(workFile, []byte("work task\n"), 0644))
+	config := fmt.Sprintf(`todo-file = "%s"
+done-file = "%s"
+
+[workspaces.work]
+todo-file = "%s"
+
+[views.all]
+workspaces = ["default", "work"]
+`, todoFile, path.Join(dir, "done.txt"), workFile)
+	configFile := path.Join(dir, "config.toml")
+	assert.Nil(t, os.WriteFile(configFile, []byte(config), 0644))
+	return configFile, todoFile, workFile
+}
+
+func runWithConfigFile(t *testing.T, configFile string, args ...string) error {
+	c := &di.Container{ConfigFile: configFile}
+	cfg := c.Config()
+	cfg.NowFunc = func() time.Time { return today }
+	c.SetConfig(cfg)
+	cmd, ctx := cmd.Root(c)
+	cmd.SetArgs(args)
+	return cmd.ExecuteContext(ctx)
+}
+
+func Test_WorkspaceFlagSelectsTodoFile(t *testing.T) {
+	configFile, todoFile, workFile := buildWorkspaceConfig(t)
+
+	assert.Nil(t, runWithConfigFile(t, configFile, "-W", "work", "add", "another work task"))
+
+	assert.Equal(t, []string{"private task"}, ReadLines(t, todoFile))
+	assert.Equal(t, []string{"work task", "2022-02-02 another work task"}, ReadLines(t, workFile))
+}
+
+func Test_UnknownWorkspaceIsAnError(t *testing.T) {
+	configFile, _, _ := buildWorkspaceConfig(t)
+
+	assert.Error(t, runWithConfigFile(t, configFile, "-W", "unknown", "--json"))
+}
+
+func Test_ChangesInCrossWorkspaceViewsAreWrittenToTheOriginalFile(t *testing.T) {
+	configFile, todoFile, workFile := buildWorkspaceConfig(t)
+
+	assert.Nil(t, runWithConfigFile(t, configFile, "all", "prioritize", "-a", "A", "-q", `workspace == "work"`))
+	assert.Nil(t, runWithConfigFile(t, configFile, "all", "prioritize", "-a", "B", "-w", "private"))
+	assert.Nil(t, runWithConfigFile(t, configFile, "-W", "work", "all", "add", "new work task"))
+
+	assert.Equal(t, []string{"(B) private task"}, ReadLines(t, todoFile))
+	assert.Equal(t, []string{"(A) work task", "2022-02-02 new work task"}, ReadLines(t, workFile))
+}
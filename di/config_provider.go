@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path"
 	"runtime"
+	"slices"
 	"time"
 
 	"github.com/Fabian-G/quest/qprojection"
@@ -24,6 +25,16 @@ const (
 
 var InternalEditTag = "quest-object-id"
 
+// The possible sources of a view
+const (
+	SourceTodo = "todo"
+	SourceDone = "done"
+	SourceBoth = "both"
+)
+
+// DefaultWorkspace is the name of the workspace that consists of the top level todo-file and done-file
+const DefaultWorkspace = "default"
+
 type StyleDef struct {
 	If string `mapstructure:"if,omitempty"`
 	Fg string `mapstructure:"fg,omitempty"`
@@ -51,6 +62,26 @@ func (m MacroDef) InDTypes() []qselect.DType {
 	return dtypes
 }
 
+type FunctionDef struct {
+	Name       string   `mapstructure:"name,omitempty"`
+	Script     string   `mapstructure:"script,omitempty"`
+	InTypes    []string `mapstructure:"args,omitempty"`
+	ResultType string   `mapstructure:"result,omitempty"`
+}
+
+func (f FunctionDef) InDTypes() []qselect.DType {
+	dtypes := make([]qselect.DType, 0, len(f.InTypes))
+	for _, t := range f.InTypes {
+		dtypes = append(dtypes, qselect.DType(t))
+	}
+	return dtypes
+}
+
+type WorkspaceDef struct {
+	TodoFile string `mapstructure:"todo-file,omitempty"`
+	DoneFile string `mapstructure:"done-file,omitempty"`
+}
+
 type ViewDef struct {
 	Description string   `mapstructure:"description,omitempty"`
 	Query       string   `mapstructure:"query,omitempty"`
@@ -61,15 +92,21 @@ type ViewDef struct {
 	Interactive bool     `mapstructure:"interactive,omitempty"`
 	AddPrefix   string   `mapstructure:"add-prefix,omitempty"`
 	AddSuffix   string   `mapstructure:"add-suffix,omitempty"`
+	Workspaces  []string `mapstructure:"workspaces,omitempty"`
+	Source      string   `mapstructure:"source,omitempty"`
+	Tree        bool     `mapstructure:"tree,omitempty"`
 }
 
 type Config struct {
 	TodoFile    string   `mapstructure:"todo-file,omitempty"`
 	DoneFile    string   `mapstructure:"done-file,omitempty"`
 	KeepBackups int      `mapstructure:"backup"`
+	History     int      `mapstructure:"history"`
 	Editor      string   `mapstructure:"editor,omitempty"`
 	UnknownTags bool     `mapstructure:"unknown-tags,omitempty"`
 	ClearOnDone []string `mapstructure:"clear-on-done,omitempty"`
+	Holidays    string   `mapstructure:"holidays-file,omitempty"`
+	Parallel    int      `mapstructure:"parallel-filter,omitempty"`
 	QuestScore  struct {
 		MinPriority    string   `mapstructure:"min-priority,omitempty"`
 		UrgencyTags    []string `mapstructure:"urgency-tags,omitempty"`
@@ -86,6 +123,11 @@ type Config struct {
 		RecTag           string `mapstructure:"rec-tag,omitempty"`
 		DueTag           string `mapstructure:"due-tag,omitempty"`
 		ThresholdTag     string `mapstructure:"threshold-tag,omitempty"`
+		UntilTag         string `mapstructure:"until-tag,omitempty"`
+		CountTag         string `mapstructure:"count-tag,omitempty"`
+		SeriesTag        string `mapstructure:"series-tag,omitempty"`
+		Horizon          string `mapstructure:"horizon,omitempty"`
+		AutoSync         bool   `mapstructure:"auto-sync,omitempty"`
 		PreservePriority bool   `mapstructure:"preserve-priority,omitempty"`
 	} `mapstructure:"recurrence,omitempty"`
 	Notes struct {
@@ -93,12 +135,48 @@ type Config struct {
 		Dir      string `mapstructure:"dir,omitempty"`
 		IdLength int    `mapstructure:"id-length,omitempty"`
 	} `mapstructure:"notes,omitempty"`
-	Styles      []StyleDef         `mapstructure:"styles"`
-	DefaultView ViewDef            `mapstructure:"default-view,omitempty"`
-	Views       map[string]ViewDef `mapstructure:"views,omitempty"`
-	Macros      []MacroDef         `mapstructure:"macro,omitempty"`
-	Tags        map[string]TagDef  `mapstructure:"tags,omitempty"`
-	NowFunc     func() time.Time   `mapstructure:"now-func,omitempty"` // Manually set only in testing, but defaults to time.Now
+	Ids struct {
+		Tag    string `mapstructure:"tag,omitempty"`
+		Length int    `mapstructure:"length,omitempty"`
+	} `mapstructure:"ids,omitempty"`
+	Dependencies struct {
+		Tag        string `mapstructure:"tag,omitempty"`
+		OnComplete string `mapstructure:"on-complete,omitempty"`
+	} `mapstructure:"dependencies,omitempty"`
+	Subtasks struct {
+		Tag              string `mapstructure:"tag,omitempty"`
+		ParentCompletion string `mapstructure:"parent-completion,omitempty"`
+	} `mapstructure:"subtasks,omitempty"`
+	Dashboard struct {
+		Layout string   `mapstructure:"layout,omitempty"`
+		Panes  []string `mapstructure:"panes,omitempty"`
+	} `mapstructure:"dashboard,omitempty"`
+	Workspace   string                  `mapstructure:"workspace,omitempty"`
+	Workspaces  map[string]WorkspaceDef `mapstructure:"workspaces,omitempty"`
+	Styles      []StyleDef              `mapstructure:"styles"`
+	DefaultView ViewDef                 `mapstructure:"default-view,omitempty"`
+	Views       map[string]ViewDef      `mapstructure:"views,omitempty"`
+	Functions   []FunctionDef           `mapstructure:"function,omitempty"`
+	Macros      []MacroDef              `mapstructure:"macro,omitempty"`
+	Tags        map[string]TagDef       `mapstructure:"tags,omitempty"`
+	NowFunc     func() time.Time        `mapstructure:"now-func,omitempty"` // Manually set only in testing, but defaults to time.Now
+}
+
+// UseWorkspace makes the todo and done file of the given workspace the active ones
+func (c *Config) UseWorkspace(name string) error {
+	ws, ok := c.Workspaces[name]
+	if !ok {
+		available := make([]string, 0, len(c.Workspaces))
+		for w := range c.Workspaces {
+			available = append(available, w)
+		}
+		slices.Sort(available)
+		return fmt.Errorf("workspace %s does not exist. Available workspaces are: %v", name, available)
+	}
+	c.Workspace = name
+	c.TodoFile = ws.TodoFile
+	c.DoneFile = ws.DoneFile
+	return nil
 }
 
 func (c Config) HumanizedTags() []string {
@@ -204,7 +282,22 @@ func buildConfig(file string) (Config, error) {
 	}
 	config.TodoFile = os.ExpandEnv(config.TodoFile)
 	config.DoneFile = os.ExpandEnv(config.DoneFile)
+	if _, ok := config.Workspaces[DefaultWorkspace]; !ok {
+		config.Workspaces[DefaultWorkspace] = WorkspaceDef{TodoFile: config.TodoFile, DoneFile: config.DoneFile}
+	}
+	for name, ws := range config.Workspaces {
+		ws.TodoFile = os.ExpandEnv(ws.TodoFile)
+		ws.DoneFile = os.ExpandEnv(ws.DoneFile)
+		if ws.DoneFile == "" {
+			ws.DoneFile = path.Join(path.Dir(ws.TodoFile), "done.txt")
+		}
+		config.Workspaces[name] = ws
+	}
+	if err := config.UseWorkspace(config.Workspace); err != nil {
+		return Config{}, err
+	}
 	config.Notes.Dir = os.ExpandEnv(config.Notes.Dir)
+	config.Holidays = os.ExpandEnv(config.Holidays)
 	config.Tags[InternalEditTag] = TagDef{
 		Type:     "int",
 		Humanize: false,
@@ -255,6 +348,7 @@ func setDefaults(v *viper.Viper, dataHome string) {
 	v.SetDefault("todo-file", path.Join(dataHome, "todo.txt"))
 	v.SetDefault("done-file", path.Join(dataHome, "done.txt"))
 	v.SetDefault("backup", 0)
+	v.SetDefault("history", 20)
 	v.SetDefault("editor", getDefaultEditor())
 	v.SetDefault("unknown-tags", true)
 	v.SetDefault("quest-score.urgency-tags", []string{"due"})
@@ -266,12 +360,26 @@ func setDefaults(v *viper.Viper, dataHome string) {
 	v.SetDefault("tracking.trim-project-prefix", false)
 	v.SetDefault("tracking.trim-context-prefix", false)
 	v.SetDefault("clear-on-done", nil)
+	v.SetDefault("parallel-filter", 0)
 	v.SetDefault("recurrence.due-tag", "due")
 	v.SetDefault("recurrence.threshold-tag", "t")
+	v.SetDefault("recurrence.until-tag", "until")
+	v.SetDefault("recurrence.count-tag", "count")
+	v.SetDefault("recurrence.series-tag", "series")
+	v.SetDefault("recurrence.horizon", "0d")
+	v.SetDefault("recurrence.auto-sync", false)
 	v.SetDefault("recurrence.preserve-priority", false)
 	v.SetDefault("notes.tag", "")
 	v.SetDefault("notes.id-length", 4)
 	v.SetDefault("notes.dir", path.Join(dataHome, "notes"))
+	v.SetDefault("ids.tag", "")
+	v.SetDefault("ids.length", 6)
+	v.SetDefault("dependencies.tag", "")
+	v.SetDefault("dependencies.on-complete", "")
+	v.SetDefault("subtasks.tag", "")
+	v.SetDefault("subtasks.parent-completion", "")
+	v.SetDefault("dashboard.layout", "tabs")
+	v.SetDefault("dashboard.panes", nil)
 	v.SetDefault("default-view.description", "Quest is a command line interface for managing your todo.txt.")
 	v.SetDefault("default-view.query", "")
 	v.SetDefault("default-view.projection", qprojection.StarProjection)
@@ -281,6 +389,10 @@ func setDefaults(v *viper.Viper, dataHome string) {
 	v.SetDefault("default-view.interactive", false)
 	v.SetDefault("default-view.add-prefix", "")
 	v.SetDefault("default-view.add-suffix", "")
+	v.SetDefault("default-view.workspaces", nil)
+	v.SetDefault("default-view.source", SourceTodo)
+	v.SetDefault("workspace", DefaultWorkspace)
+	v.SetDefault("workspaces", make(map[string]WorkspaceDef))
 	v.SetDefault("tags", make(map[string]TagDef))
 	v.SetDefault("now-func", time.Now)
 
@@ -294,6 +406,8 @@ func setDefaults(v *viper.Viper, dataHome string) {
 		v.SetDefault("views."+viewName+".interactive", v.GetBool("default-view.interactive"))
 		v.SetDefault("views."+viewName+".add-prefix", v.GetString("default-view.add-prefix"))
 		v.SetDefault("views."+viewName+".add-suffix", v.GetString("default-view.add-suffix"))
+		v.SetDefault("views."+viewName+".workspaces", v.GetStringSlice("default-view.workspaces"))
+		v.SetDefault("views."+viewName+".source", v.GetString("default-view.source"))
 	}
 }
 
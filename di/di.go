@@ -3,6 +3,8 @@ package di
 import (
 	"log"
 
+	"github.com/Fabian-G/quest/hook"
+	"github.com/Fabian-G/quest/qjournal"
 	"github.com/Fabian-G/quest/qprojection"
 	"github.com/Fabian-G/quest/qscore"
 	"github.com/Fabian-G/quest/qsort"
@@ -15,16 +17,34 @@ type Container struct {
 	config               *Config
 	repo                 *todotxt.Repo
 	doneRepo             *todotxt.Repo
+	workspaceRepos       map[string]*todotxt.Repo
+	workspaceDoneRepos   map[string]*todotxt.Repo
 	notesRepo            *todotxt.NotesRepo
+	identity             *hook.Identity
+	recurrence           *hook.Recurrence
+	journal              *qjournal.Journal
 	questScoreCalculator *qscore.Calculator
 	sortCompiler         *qsort.Compiler
 	projector            map[string]*qprojection.Projector
 	editor               Editor
+	notify               func(string)
+}
+
+// SetNotify sets where the hooks send their notifications to (e.g. that a task is no longer blocked).
+// Without it notifications are discarded.
+func (d *Container) SetNotify(notify func(msg string)) {
+	d.notify = notify
+}
+
+func (d *Container) notifyHooks(msg string) {
+	if d.notify != nil {
+		d.notify(msg)
+	}
 }
 
 func (d *Container) TodoTxtRepo() *todotxt.Repo {
 	if d.repo == nil {
-		d.repo = buildTodoTxtRepo(d.Config())
+		d.repo = buildTodoTxtRepo(d.Config(), d.notifyHooks)
 	}
 	return d.repo
 }
@@ -36,6 +56,42 @@ func (d *Container) DoneTxtRepo() *todotxt.Repo {
 	return d.doneRepo
 }
 
+// WorkspaceRepo returns the repo for the todo file of the given workspace
+func (d *Container) WorkspaceRepo(workspace string) (*todotxt.Repo, error) {
+	config := d.Config()
+	if workspace == config.Workspace {
+		return d.TodoTxtRepo(), nil
+	}
+	if d.workspaceRepos == nil {
+		d.workspaceRepos = make(map[string]*todotxt.Repo)
+	}
+	if _, ok := d.workspaceRepos[workspace]; !ok {
+		if err := config.UseWorkspace(workspace); err != nil {
+			return nil, err
+		}
+		d.workspaceRepos[workspace] = buildTodoTxtRepo(config, d.notifyHooks)
+	}
+	return d.workspaceRepos[workspace], nil
+}
+
+// WorkspaceDoneRepo returns the repo for the done file of the given workspace
+func (d *Container) WorkspaceDoneRepo(workspace string) (*todotxt.Repo, error) {
+	config := d.Config()
+	if workspace == config.Workspace {
+		return d.DoneTxtRepo(), nil
+	}
+	if d.workspaceDoneRepos == nil {
+		d.workspaceDoneRepos = make(map[string]*todotxt.Repo)
+	}
+	if _, ok := d.workspaceDoneRepos[workspace]; !ok {
+		if err := config.UseWorkspace(workspace); err != nil {
+			return nil, err
+		}
+		d.workspaceDoneRepos[workspace] = buildDoneTxtRepo(config)
+	}
+	return d.workspaceDoneRepos[workspace], nil
+}
+
 func (d *Container) NotesRepo() *todotxt.NotesRepo {
 	if d.notesRepo == nil {
 		d.notesRepo = buildNotesRepo(d.Config())
@@ -43,6 +99,28 @@ func (d *Container) NotesRepo() *todotxt.NotesRepo {
 	return d.notesRepo
 }
 
+func (d *Container) Identity() *hook.Identity {
+	if d.identity == nil {
+		d.identity = buildIdentity(d.Config())
+	}
+	return d.identity
+}
+
+// Recurrence returns nil if recurrence is disabled
+func (d *Container) Recurrence() *hook.Recurrence {
+	if d.recurrence == nil {
+		d.recurrence = buildRecurrence(d.Config())
+	}
+	return d.recurrence
+}
+
+func (d *Container) Journal() *qjournal.Journal {
+	if d.journal == nil {
+		d.journal = buildJournal(d.Config())
+	}
+	return d.journal
+}
+
 func (d *Container) Config() Config {
 	if d.config == nil {
 		var err error
@@ -72,7 +150,12 @@ func (d *Container) SortCompiler() qsort.Compiler {
 }
 
 func (d *Container) Projector(cmd *cobra.Command) qprojection.Projector {
-	view := cmd.Name()
+	return d.ViewProjector(cmd.Name())
+}
+
+// ViewProjector returns the projector of the view with the given name.
+// Unknown names refer to the default view.
+func (d *Container) ViewProjector(view string) qprojection.Projector {
 	if d.projector == nil {
 		d.projector = make(map[string]*qprojection.Projector)
 	}
@@ -0,0 +1,12 @@
+package di
+
+import (
+	"github.com/Fabian-G/quest/hook"
+)
+
+func buildIdentity(c Config) *hook.Identity {
+	if len(c.Ids.Tag) == 0 {
+		return nil
+	}
+	return hook.NewIdentity(c.Ids.Tag, c.Ids.Length)
+}
@@ -0,0 +1,13 @@
+package di
+
+import (
+	"github.com/Fabian-G/quest/qjournal"
+)
+
+func buildJournal(c Config) *qjournal.Journal {
+	journal := qjournal.NewJournal(qjournal.Location(c.TodoFile), c.History)
+	if c.NowFunc != nil {
+		journal.NowFunc = c.NowFunc
+	}
+	return journal
+}
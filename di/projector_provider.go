@@ -13,5 +13,6 @@ func buildProjector(c Config, view ViewDef, calc qscore.Calculator) qprojection.
 		TagTypes:      c.TagTypes(),
 		TagColors:     c.TagColors(),
 		LineColors:    c.LineColors(),
+		IdTag:         c.Ids.Tag,
 	}
 }
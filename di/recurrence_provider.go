@@ -0,0 +1,20 @@
+package di
+
+import (
+	"github.com/Fabian-G/quest/hook"
+)
+
+func buildRecurrence(c Config) *hook.Recurrence {
+	if c.Recurrence.RecTag == "" {
+		return nil
+	}
+	recurrence := hook.NewRecurrence(hook.RecurrenceTags{
+		Rec:       c.Recurrence.RecTag,
+		Due:       c.Recurrence.DueTag,
+		Threshold: c.Recurrence.ThresholdTag,
+		Until:     c.Recurrence.UntilTag,
+		Count:     c.Recurrence.CountTag,
+		Series:    c.Recurrence.SeriesTag,
+	}, hook.WithNowFunc(c.NowFunc), hook.WithPreservePriority(c.Recurrence.PreservePriority), hook.WithIdentity(buildIdentity(c)))
+	return &recurrence
+}
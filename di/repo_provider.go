@@ -10,20 +10,23 @@ import (
 	"github.com/Fabian-G/quest/todotxt"
 )
 
-func buildTodoTxtRepo(c Config) *todotxt.Repo {
+func buildTodoTxtRepo(c Config, notify func(string)) *todotxt.Repo {
 	repo := todotxt.NewRepo(c.TodoFile)
-	repo.DefaultHooks = hooks(c)
+	repo.DefaultHooks = hooks(c, notify)
 	repo.Keep = c.KeepBackups
+	repo.Name = c.Workspace
 	return repo
 }
 
 func buildDoneTxtRepo(c Config) *todotxt.Repo {
 	repo := todotxt.NewRepo(c.DoneFile)
 	repo.Keep = c.KeepBackups
+	repo.Name = c.Workspace
+	repo.Archive = true
 	return repo
 }
 
-func hooks(c Config) []todotxt.Hook {
+func hooks(c Config, notify func(string)) []todotxt.Hook {
 	hooks := make([]todotxt.Hook, 0)
 	tagTypes := c.TagTypes()
 	hooks = append(hooks, hook.NewTagExpansion(c.UnknownTags, tagTypes))
@@ -31,12 +34,19 @@ func hooks(c Config) []todotxt.Hook {
 	if len(c.ClearOnDone) > 0 {
 		hooks = append(hooks, hook.ClearOnDone{Clear: c.ClearOnDone})
 	}
-	if recTag := c.Recurrence.RecTag; recTag != "" {
-		hooks = append(hooks, hook.NewRecurrence(hook.RecurrenceTags{
-			Rec:       c.Recurrence.RecTag,
-			Due:       c.Recurrence.DueTag,
-			Threshold: c.Recurrence.ThresholdTag,
-		}, hook.WithNowFunc(c.NowFunc), hook.WithPreservePriority(c.Recurrence.PreservePriority)))
+	if c.Dependencies.Tag != "" && c.Ids.Tag != "" {
+		dependencies := hook.NewDependencies(c.Dependencies.Tag, c.Ids.Tag)
+		dependencies.OnComplete = c.Dependencies.OnComplete
+		dependencies.Notify = notify
+		hooks = append(hooks, dependencies)
+	}
+	if c.Subtasks.Tag != "" && c.Ids.Tag != "" {
+		subtasks := hook.NewSubtasks(c.Subtasks.Tag, c.Ids.Tag)
+		subtasks.ParentCompletion = c.Subtasks.ParentCompletion
+		hooks = append(hooks, subtasks)
+	}
+	if recurrence := buildRecurrence(c); recurrence != nil {
+		hooks = append(hooks, *recurrence)
 	}
 	if timew, err := exec.LookPath("timew"); err == nil && len(c.Tracking.Tag) > 0 {
 		tracking := hook.NewTracking(c.Tracking.Tag, &timeWarrior{timew: timew})
@@ -45,6 +55,9 @@ func hooks(c Config) []todotxt.Hook {
 		tracking.IncludeTags = c.Tracking.IncludeTags
 		hooks = append(hooks, tracking)
 	}
+	if identity := buildIdentity(c); identity != nil {
+		hooks = append(hooks, identity)
+	}
 	return hooks
 }
 
@@ -0,0 +1,144 @@
+package hook
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+// The actions that can be taken when a blocker is completed
+const (
+	OnCompleteNotify  = "notify"
+	OnCompleteUnblock = "unblock"
+)
+
+// Dependencies validates the dependency tag, which refers to the ids of the tasks a task depends on.
+// References must point to existing tasks when they are added and must not form cycles.
+type Dependencies struct {
+	Tag   string
+	IdTag string
+	// OnComplete is one of "", OnCompleteNotify or OnCompleteUnblock.
+	// Notify reports every dependent that is no longer blocked to Notify, unblock removes the reference to the completed task.
+	OnComplete string
+	Notify     func(msg string)
+}
+
+func NewDependencies(tag string, idTag string) *Dependencies {
+	return &Dependencies{
+		Tag:   tag,
+		IdTag: idTag,
+	}
+}
+
+func (d Dependencies) OnMod(list *todotxt.List, event todotxt.ModEvent) error {
+	if event.Current == nil {
+		return nil
+	}
+	if deps := qselect.DependenciesOf(event.Current, d.Tag); event.Previous == nil || !slices.Equal(deps, qselect.DependenciesOf(event.Previous, d.Tag)) {
+		if err := d.validateReferences(list, event.Current, deps); err != nil {
+			return err
+		}
+	}
+	if event.IsCompleteEvent() {
+		if err := d.onComplete(list, event.Current); err != nil {
+			return err
+		}
+	}
+	return d.OnValidate(list, todotxt.ValidationEvent{Item: event.Current})
+}
+
+func (d Dependencies) OnValidate(list *todotxt.List, event todotxt.ValidationEvent) error {
+	deps := qselect.DependenciesOf(event.Item, d.Tag)
+	if len(deps) == 0 {
+		return nil
+	}
+	id := d.idOf(event.Item)
+	if id == "" {
+		// Nobody can refer to the item, so it can not be part of a cycle
+		return nil
+	}
+	byId := make(map[string]*todotxt.Item)
+	for _, t := range list.Tasks() {
+		if tId := d.idOf(t); tId != "" {
+			byId[tId] = t
+		}
+	}
+	if cycle := d.findCycle(byId, id, []string{id}, make(map[string]struct{})); cycle != nil {
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle searches for a path from the last element of path back to start
+func (d Dependencies) findCycle(byId map[string]*todotxt.Item, start string, path []string, visited map[string]struct{}) []string {
+	item, ok := byId[path[len(path)-1]]
+	if !ok {
+		return nil
+	}
+	for _, dep := range qselect.DependenciesOf(item, d.Tag) {
+		if dep == start {
+			return append(path, dep)
+		}
+		if _, ok := visited[dep]; ok {
+			continue
+		}
+		visited[dep] = struct{}{}
+		if cycle := d.findCycle(byId, start, append(path, dep), visited); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func (d Dependencies) validateReferences(list *todotxt.List, item *todotxt.Item, deps []string) error {
+	for _, dep := range deps {
+		if dep == d.idOf(item) {
+			return fmt.Errorf("task can not depend on itself (%s:%s)", d.Tag, dep)
+		}
+		if !slices.ContainsFunc(list.Tasks(), func(t *todotxt.Item) bool { return d.idOf(t) == dep }) {
+			return fmt.Errorf("task depends on unknown task id %s", dep)
+		}
+	}
+	return nil
+}
+
+func (d Dependencies) onComplete(list *todotxt.List, blocker *todotxt.Item) error {
+	id := d.idOf(blocker)
+	if id == "" || d.OnComplete == "" {
+		return nil
+	}
+	for _, t := range list.Tasks() {
+		deps := qselect.DependenciesOf(t, d.Tag)
+		if t.Done() || !slices.Contains(deps, id) {
+			continue
+		}
+		switch d.OnComplete {
+		case OnCompleteUnblock:
+			remaining := slices.DeleteFunc(deps, func(dep string) bool { return dep == id })
+			if err := t.SetTag(d.Tag, strings.Join(remaining, ",")); err != nil {
+				return err
+			}
+		case OnCompleteNotify:
+			if d.Notify != nil && !d.blocked(list, t, deps) {
+				d.Notify(fmt.Sprintf("Task #%d is no longer blocked: %s", list.LineOf(t), t.Description()))
+			}
+		}
+	}
+	return nil
+}
+
+func (d Dependencies) blocked(list *todotxt.List, item *todotxt.Item, deps []string) bool {
+	return slices.ContainsFunc(list.Tasks(), func(t *todotxt.Item) bool {
+		return t != item && !t.Done() && slices.Contains(deps, d.idOf(t))
+	})
+}
+
+func (d Dependencies) idOf(item *todotxt.Item) string {
+	if values := item.TagValues(d.IdTag); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
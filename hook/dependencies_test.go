@@ -0,0 +1,67 @@
+package hook_test
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/hook"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DependenciesRejectUnknownReferences(t *testing.T) {
+	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("write tests id:a")))
+	list.AddHook(hook.NewDependencies("after", "id"))
+
+	assert.Error(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("release id:b after:x"))))
+	assert.Error(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("release id:b after:b"))))
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("release id:b after:a"))))
+}
+
+func Test_DependenciesRejectCycles(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("write tests id:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write code id:b after:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("release id:c after:b")),
+	)
+	list.AddHook(hook.NewDependencies("after", "id"))
+
+	err := list.GetLine(1).SetTag("after", "c")
+
+	assert.ErrorContains(t, err, "a -> c -> b -> a")
+	assert.Empty(t, list.GetLine(1).Tags()["after"])
+}
+
+func Test_DependenciesUnblockDependentsOnComplete(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("write tests id:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write code id:b")),
+		todotxt.MustBuildItem(todotxt.WithDescription("release id:c after:a,b")),
+	)
+	dependencies := hook.NewDependencies("after", "id")
+	dependencies.OnComplete = hook.OnCompleteUnblock
+	list.AddHook(dependencies)
+
+	assert.Nil(t, list.GetLine(1).Complete())
+
+	assert.Equal(t, []string{"b"}, list.GetLine(3).Tags()["after"])
+}
+
+func Test_DependenciesNotifyWhenTheLastBlockerIsCompleted(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("write tests id:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write code id:b")),
+		todotxt.MustBuildItem(todotxt.WithDescription("release id:c after:a,b")),
+	)
+	notifications := make([]string, 0)
+	dependencies := hook.NewDependencies("after", "id")
+	dependencies.OnComplete = hook.OnCompleteNotify
+	dependencies.Notify = func(msg string) { notifications = append(notifications, msg) }
+	list.AddHook(dependencies)
+
+	assert.Nil(t, list.GetLine(1).Complete())
+	assert.Empty(t, notifications)
+	assert.Nil(t, list.GetLine(2).Complete())
+
+	assert.Equal(t, []string{"Task #3 is no longer blocked: release id:c after:a,b"}, notifications)
+	assert.Equal(t, []string{"a,b"}, list.GetLine(3).Tags()["after"])
+}
@@ -0,0 +1,124 @@
+package hook
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+var maxIdIterations = 1000
+
+var ErrNoFreeId = errors.New("could not find a new task id in a reasonable amount of time. Consider increasing the id length")
+
+// Identity gives every task a stable id that does not change
+// when the line number changes. Tasks that are added without an id
+// (or with an id that is already taken) receive a fresh one.
+type Identity struct {
+	Tag    string
+	Length int
+}
+
+func NewIdentity(tag string, length int) *Identity {
+	return &Identity{
+		Tag:    tag,
+		Length: length,
+	}
+}
+
+func (i Identity) OnMod(list *todotxt.List, event todotxt.ModEvent) error {
+	if event.Current == nil {
+		return nil
+	}
+	prevId := i.idOfPrevious(event)
+	if prevId != "" && i.IdOf(event.Current) == "" {
+		// Ids are stable, so removing the tag restores the old one
+		return event.Current.SetTag(i.Tag, prevId)
+	}
+	id := i.IdOf(event.Current)
+	if id != "" && id == prevId {
+		// The id did not change, so it is still unique
+		return nil
+	}
+	taken := make(map[string]struct{})
+	for _, t := range list.Tasks() {
+		if t != event.Current {
+			taken[i.IdOf(t)] = struct{}{}
+		}
+	}
+	if _, ok := taken[id]; id != "" && !ok {
+		return nil
+	}
+	newId, err := i.newId(taken)
+	if err != nil {
+		return err
+	}
+	return event.Current.SetTag(i.Tag, newId)
+}
+
+func (i Identity) OnValidate(list *todotxt.List, event todotxt.ValidationEvent) error {
+	return nil
+}
+
+// Assign gives all items that do not have an id yet a fresh one.
+// Hooks that add tasks to the list (e.g. recurrence) use it, because hooks do not fire for these tasks.
+func (i Identity) Assign(list *todotxt.List, items ...*todotxt.Item) error {
+	taken := make(map[string]struct{})
+	for _, t := range list.Tasks() {
+		taken[i.IdOf(t)] = struct{}{}
+	}
+	for _, item := range items {
+		if i.IdOf(item) != "" {
+			continue
+		}
+		id, err := i.newId(taken)
+		if err != nil {
+			return err
+		}
+		if err := item.SetTag(i.Tag, id); err != nil {
+			return err
+		}
+		taken[id] = struct{}{}
+	}
+	return nil
+}
+
+func (i Identity) IdOf(item *todotxt.Item) string {
+	if values := item.Tags()[i.Tag]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (i Identity) idOfPrevious(event todotxt.ModEvent) string {
+	if event.Previous == nil {
+		return ""
+	}
+	return i.IdOf(event.Previous)
+}
+
+// newId returns a random id that is not part of taken
+func (i Identity) newId(taken map[string]struct{}) (string, error) {
+	for j := 0; j < maxIdIterations; j++ {
+		id := nAlphaNum(i.Length)
+		if _, ok := taken[id]; !ok {
+			return id, nil
+		}
+	}
+	return "", ErrNoFreeId
+}
+
+func nAlphaNum(n int) string {
+	result := strings.Builder{}
+	for i := 0; i < n; i++ {
+		r := rand.Intn(36)
+		if r <= 9 {
+			_, _ = result.WriteString(strconv.Itoa(r))
+		} else {
+			_, _ = result.WriteRune(rune('a' + (r - 10)))
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,70 @@
+package hook_test
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/hook"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IdentityAssignsIdsToNewTasks(t *testing.T) {
+	list := todotxt.ListOf()
+	list.AddHook(hook.NewIdentity("id", 5))
+
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("A new task"))))
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("A task with id id:abc"))))
+
+	ids := list.GetLine(1).Tags()["id"]
+	assert.Len(t, ids, 1)
+	assert.Regexp(t, "^[0-9a-z]{5}$", ids[0])
+	assert.Equal(t, []string{"abc"}, list.GetLine(2).Tags()["id"])
+}
+
+func Test_IdentityResolvesDuplicateIds(t *testing.T) {
+	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("A task id:abc")))
+	list.AddHook(hook.NewIdentity("id", 5))
+
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("A copy id:abc"))))
+
+	assert.Equal(t, []string{"abc"}, list.GetLine(1).Tags()["id"])
+	assert.NotEqual(t, []string{"abc"}, list.GetLine(2).Tags()["id"])
+}
+
+func Test_IdentityRestoresRemovedIds(t *testing.T) {
+	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("A task id:abc")))
+	list.AddHook(hook.NewIdentity("id", 5))
+
+	assert.Nil(t, list.GetLine(1).SetTag("id", ""))
+
+	assert.Equal(t, []string{"abc"}, list.GetLine(1).Tags()["id"])
+}
+
+func Test_IdentityGivesRecurringTasksANewId(t *testing.T) {
+	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("A task rec:1w due:2023-01-01 id:abc")))
+	identity := hook.NewIdentity("id", 5)
+	list.AddHook(hook.NewRecurrence(defaultTags, hook.WithIdentity(identity)))
+	list.AddHook(identity)
+
+	assert.Nil(t, list.GetLine(1).Complete())
+
+	assert.Equal(t, 2, list.Len())
+	assert.Equal(t, []string{"abc"}, list.GetLine(1).Tags()["id"])
+	assert.Len(t, list.GetLine(2).Tags()["id"], 1)
+	assert.NotEqual(t, "abc", list.GetLine(2).Tags()["id"][0])
+}
+
+func Test_IdentityOnlyTouchesTheModifiedTask(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("A task id:abc")),
+		todotxt.MustBuildItem(todotxt.WithDescription("A copy id:abc")),
+		todotxt.MustBuildItem(todotxt.WithDescription("Another task id:def")),
+	)
+	list.AddHook(hook.NewIdentity("id", 5))
+
+	assert.Nil(t, list.GetLine(3).SetTag("due", "2023-01-01"))
+
+	assert.Equal(t, []string{"abc"}, list.GetLine(1).Tags()["id"])
+	assert.Equal(t, []string{"abc"}, list.GetLine(2).Tags()["id"])
+	assert.Equal(t, []string{"def"}, list.GetLine(3).Tags()["id"])
+}
@@ -3,6 +3,8 @@ package hook
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +12,9 @@ import (
 	"github.com/Fabian-G/quest/todotxt"
 )
 
+// seriesIdLength is the length of the ids that link the occurrences of a recurrent task
+const seriesIdLength = 6
+
 var ErrNoRecurrenceBase = errors.New("when the recurrence tag is set, either the due tag or the threshold tag (or both) must be set")
 
 type recurrenceParams struct {
@@ -18,12 +23,16 @@ type recurrenceParams struct {
 	due       time.Time
 	threshold time.Time
 	duration  qduration.Duration
+	rule      *rrule
 	relative  bool
+	until     time.Time
+	count     int
 }
 
 type Recurrence struct {
 	tags             RecurrenceTags
 	preservePriority bool
+	identity         *Identity
 	nowFunc          func() time.Time
 }
 
@@ -31,9 +40,15 @@ type RecurrenceTags struct {
 	Rec       string
 	Due       string
 	Threshold string
+	// Until and Count are optional. They end the recurrence after a date or a number of occurrences.
+	Until string
+	Count string
+	// Series is optional. All occurrences of a recurrent task share the same series id,
+	// which prevents an occurrence from being spawned twice.
+	Series string
 }
 
-func NewRecurrence(tags RecurrenceTags, opts ...func(Recurrence) Recurrence) todotxt.Hook {
+func NewRecurrence(tags RecurrenceTags, opts ...func(Recurrence) Recurrence) Recurrence {
 	rec := Recurrence{
 		tags: tags,
 	}
@@ -50,6 +65,14 @@ func WithPreservePriority(preserve bool) func(r Recurrence) Recurrence {
 	}
 }
 
+// WithIdentity gives spawned occurrences a fresh id instead of the one of their predecessor
+func WithIdentity(identity *Identity) func(r Recurrence) Recurrence {
+	return func(r Recurrence) Recurrence {
+		r.identity = identity
+		return r
+	}
+}
+
 func WithNowFunc(now func() time.Time) func(r Recurrence) Recurrence {
 	return func(r Recurrence) Recurrence {
 		r.nowFunc = now
@@ -71,10 +94,8 @@ func (r Recurrence) OnMod(list *todotxt.List, event todotxt.ModEvent) error {
 		return err
 	}
 
-	if param.relative {
-		return r.spawnRelative(param)
-	}
-	return r.spawnAbsolute(param)
+	_, err = r.spawn(param)
+	return err
 }
 
 func (r Recurrence) OnValidate(list *todotxt.List, event todotxt.ValidationEvent) error {
@@ -88,7 +109,122 @@ func (r Recurrence) OnValidate(list *todotxt.List, event todotxt.ValidationEvent
 	return nil
 }
 
-func (r Recurrence) spawnRelative(params recurrenceParams) error {
+func (r Recurrence) spawn(params recurrenceParams) (*todotxt.Item, error) {
+	if params.relative {
+		return r.spawnRelative(params)
+	}
+	return r.spawnAbsolute(params)
+}
+
+// Sync materializes the occurrences of all absolute recurrent tasks up to (and including) the day horizon.
+// This way occurrences show up even if the previous one has not been completed yet.
+// Tasks without a series id get a new one, so that no occurrence is spawned twice. It returns the spawned tasks.
+func (r Recurrence) Sync(list *todotxt.List, horizon time.Time) ([]*todotxt.Item, error) {
+	if r.tags.Series == "" {
+		return nil, errors.New("syncing recurrent tasks requires a series tag")
+	}
+	latest := make(map[string]recurrenceParams)
+	seriesOrder := make([]string, 0)
+	for _, t := range list.Tasks() {
+		if rec := t.Tags()[r.tags.Rec]; len(rec) == 0 || !strings.HasPrefix(rec[0], "+") {
+			continue
+		}
+		if t.Done() && r.seriesOf(t) == "" {
+			// Done tasks that are not linked to a series already spawned their successor
+			continue
+		}
+		series, err := r.assignSeries(list, t)
+		if err != nil {
+			return nil, err
+		}
+		params, err := r.parseRecurrenceParams(list, todotxt.ModEvent{Previous: t, Current: t})
+		if err != nil {
+			return nil, err
+		}
+		prev, ok := latest[series]
+		if !ok {
+			seriesOrder = append(seriesOrder, series)
+		}
+		if !ok || params.primaryDate().After(prev.primaryDate()) {
+			latest[series] = params
+		}
+	}
+
+	spawned := make([]*todotxt.Item, 0)
+	for _, series := range seriesOrder {
+		params := latest[series]
+		for {
+			next := params.next(params.primaryDate())
+			if next.After(horizon) || !next.After(params.primaryDate()) {
+				break
+			}
+			item, err := r.spawnAbsolute(params)
+			if err != nil {
+				return nil, err
+			}
+			if item == nil {
+				break
+			}
+			spawned = append(spawned, item)
+			if params, err = r.parseRecurrenceParams(list, todotxt.ModEvent{Previous: item, Current: item}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return spawned, nil
+}
+
+// Skip removes item without completing it and spawns the next occurrence instead.
+func (r Recurrence) Skip(list *todotxt.List, item *todotxt.Item) error {
+	if len(item.Tags()[r.tags.Rec]) == 0 {
+		return fmt.Errorf("task \"%s\" is not recurrent", item.Description())
+	}
+	params, err := r.parseRecurrenceParams(list, todotxt.ModEvent{Previous: item, Current: item})
+	if err != nil {
+		return err
+	}
+	if _, err := r.spawn(params); err != nil {
+		return err
+	}
+	return list.Remove(list.LineOf(item))
+}
+
+func (r Recurrence) seriesOf(item *todotxt.Item) string {
+	if series := item.Tags()[r.tags.Series]; len(series) > 0 {
+		return series[0]
+	}
+	return ""
+}
+
+// assignSeries returns the series id of item and assigns a fresh one if it does not have one yet.
+func (r Recurrence) assignSeries(list *todotxt.List, item *todotxt.Item) (string, error) {
+	if series := r.seriesOf(item); series != "" {
+		return series, nil
+	}
+	for j := 0; j < maxIdIterations; j++ {
+		series := nAlphaNum(seriesIdLength)
+		if slices.ContainsFunc(list.Tasks(), func(t *todotxt.Item) bool { return r.seriesOf(t) == series }) {
+			continue
+		}
+		return series, item.SetTag(r.tags.Series, series)
+	}
+	return "", ErrNoFreeId
+}
+
+// isDuplicate reports whether another occurrence of the same series with the same dates exists
+func (r Recurrence) isDuplicate(list *todotxt.List, newItem *todotxt.Item) bool {
+	series := r.seriesOf(newItem)
+	if series == "" {
+		return false
+	}
+	return slices.ContainsFunc(list.Tasks(), func(t *todotxt.Item) bool {
+		return t != newItem && r.seriesOf(t) == series &&
+			slices.Equal(t.Tags()[r.tags.Due], newItem.Tags()[r.tags.Due]) &&
+			slices.Equal(t.Tags()[r.tags.Threshold], newItem.Tags()[r.tags.Threshold])
+	})
+}
+
+func (r Recurrence) spawnRelative(params recurrenceParams) (*todotxt.Item, error) {
 	newItem := params.base
 	var zeroTime = time.Time{}
 	var completionDate time.Time
@@ -100,48 +236,53 @@ func (r Recurrence) spawnRelative(params recurrenceParams) error {
 
 	switch {
 	case params.threshold != zeroTime && params.due != zeroTime:
-		newThreshold := params.duration.AddTo(completionDate)
+		newThreshold := params.next(completionDate)
 		err := newItem.SetTag(r.tags.Threshold, newThreshold.Format(time.DateOnly))
 		if err != nil {
-			return fmt.Errorf("failed to set new threshold date when trying to spawn new recurrent task")
+			return nil, fmt.Errorf("failed to set new threshold date when trying to spawn new recurrent task")
 		}
 		diff := max(params.due.Sub(params.threshold), 0)
 		err = newItem.SetTag(r.tags.Due, newThreshold.Add(diff).Format(time.DateOnly))
 		if err != nil {
-			return fmt.Errorf("failed to set new due date when trying to spawn new recurrent task")
+			return nil, fmt.Errorf("failed to set new due date when trying to spawn new recurrent task")
 		}
 	case params.threshold != zeroTime:
-		newThreshold := params.duration.AddTo(completionDate)
+		newThreshold := params.next(completionDate)
 		err := newItem.SetTag(r.tags.Threshold, newThreshold.Format(time.DateOnly))
 		if err != nil {
-			return fmt.Errorf("failed to set new threshold date when trying to spawn new recurrent task")
+			return nil, fmt.Errorf("failed to set new threshold date when trying to spawn new recurrent task")
 		}
 	case params.due != zeroTime:
-		newDue := params.duration.AddTo(completionDate)
+		newDue := params.next(completionDate)
 		err := newItem.SetTag(r.tags.Due, newDue.Format(time.DateOnly))
 		if err != nil {
-			return fmt.Errorf("failed to set new due date when trying to spawn new recurrent task")
+			return nil, fmt.Errorf("failed to set new due date when trying to spawn new recurrent task")
 		}
 	}
-	return params.list.Add(newItem)
+	return r.addUnlessEnded(params, newItem)
 }
 
-func (r Recurrence) spawnAbsolute(params recurrenceParams) error {
+func (r Recurrence) spawnAbsolute(params recurrenceParams) (*todotxt.Item, error) {
 	newItem := params.base
 	var zeroTime = time.Time{}
 	if params.due != zeroTime {
-		err := newItem.SetTag(r.tags.Due, params.duration.AddTo(params.due).Format(time.DateOnly))
+		err := newItem.SetTag(r.tags.Due, params.next(params.due).Format(time.DateOnly))
 		if err != nil {
-			return fmt.Errorf("failed to set new due date when trying to spawn new recurrent task")
+			return nil, fmt.Errorf("failed to set new due date when trying to spawn new recurrent task")
 		}
 	}
 	if params.threshold != zeroTime {
-		err := newItem.SetTag(r.tags.Threshold, params.duration.AddTo(params.threshold).Format(time.DateOnly))
+		newThreshold := params.next(params.threshold)
+		if params.rule != nil && params.due != zeroTime {
+			// A rule would move both dates to the same day, so keep the distance to the due date instead
+			newThreshold = params.next(params.due).Add(params.threshold.Sub(params.due))
+		}
+		err := newItem.SetTag(r.tags.Threshold, newThreshold.Format(time.DateOnly))
 		if err != nil {
-			return fmt.Errorf("failed to set new threshold date when trying to spawn new recurrent task")
+			return nil, fmt.Errorf("failed to set new threshold date when trying to spawn new recurrent task")
 		}
 	}
-	return params.list.Add(newItem)
+	return r.addUnlessEnded(params, newItem)
 }
 
 func (r Recurrence) parseRecurrenceParams(list *todotxt.List, event todotxt.ModEvent) (recurrenceParams, error) {
@@ -189,14 +330,93 @@ func (r Recurrence) parseRecurrenceParams(list *todotxt.List, event todotxt.ModE
 	}
 
 	params.relative = !strings.HasPrefix(rec, "+")
-	duration, err := qduration.Parse(rec)
-	if err != nil {
-		return params, fmt.Errorf("could not parse duration %s: %w", rec, err)
+	if isRRule(rec) {
+		rule, err := parseRRule(strings.TrimPrefix(rec, "+"))
+		if err != nil {
+			return params, fmt.Errorf("could not parse recurrence rule %s: %w", rec, err)
+		}
+		if _, ok := rule.next(params.primaryDate()); !ok {
+			return params, fmt.Errorf("recurrence rule %s has no further occurrence", rec)
+		}
+		params.rule = &rule
+	} else {
+		duration, err := qduration.Parse(rec)
+		if err != nil {
+			return params, fmt.Errorf("could not parse duration %s: %w", rec, err)
+		}
+		params.duration = duration.Abs()
+	}
+
+	if until := tags[r.tags.Until]; r.tags.Until != "" && len(until) > 0 {
+		untilDate, err := time.Parse(time.DateOnly, until[0])
+		if err != nil {
+			return params, fmt.Errorf("could not parse until date %s: %w", until[0], err)
+		}
+		params.until = untilDate
+	}
+	if count := tags[r.tags.Count]; r.tags.Count != "" && len(count) > 0 {
+		c, err := strconv.Atoi(count[0])
+		if err != nil || c < 1 {
+			return params, fmt.Errorf("invalid count %s: must be a positive integer", count[0])
+		}
+		params.count = c
 	}
-	params.duration = duration.Abs()
 	return params, nil
 }
 
+// addUnlessEnded adds the spawned item to the list, unless the recurrence ended because
+// the count was exhausted or the new date is after the until date. Occurrences that already exist are not added either.
+// It returns the added item or nil if it was not added.
+func (r Recurrence) addUnlessEnded(params recurrenceParams, newItem *todotxt.Item) (*todotxt.Item, error) {
+	if params.count == 1 {
+		return nil, nil
+	}
+	if params.count > 1 {
+		if err := newItem.SetTag(r.tags.Count, strconv.Itoa(params.count-1)); err != nil {
+			return nil, fmt.Errorf("failed to decrement count when trying to spawn new recurrent task")
+		}
+	}
+	var zeroTime = time.Time{}
+	if params.until != zeroTime {
+		newDate := newItem.Tags()[r.tags.Due]
+		if len(newDate) == 0 {
+			newDate = newItem.Tags()[r.tags.Threshold]
+		}
+		if d, err := time.Parse(time.DateOnly, newDate[0]); err == nil && d.After(params.until) {
+			return nil, nil
+		}
+	}
+	if r.isDuplicate(params.list, newItem) {
+		return nil, nil
+	}
+	if r.identity != nil {
+		if err := newItem.SetTag(r.identity.Tag, ""); err != nil {
+			return nil, err
+		}
+		if err := r.identity.Assign(params.list, newItem); err != nil {
+			return nil, err
+		}
+	}
+	return newItem, params.list.Add(newItem)
+}
+
+// next returns the next date after t according to the recurrence rule or duration.
+func (p recurrenceParams) next(t time.Time) time.Time {
+	if p.rule != nil {
+		next, _ := p.rule.next(t)
+		return next
+	}
+	return p.duration.AddTo(t)
+}
+
+// primaryDate is the due date if present and the threshold date otherwise
+func (p recurrenceParams) primaryDate() time.Time {
+	if p.due != (time.Time{}) {
+		return p.due
+	}
+	return p.threshold
+}
+
 func (r Recurrence) now() time.Time {
 	if r.nowFunc != nil {
 		return r.nowFunc()
@@ -14,6 +14,9 @@ var defaultTags = hook.RecurrenceTags{
 	Rec:       "rec",
 	Due:       "due",
 	Threshold: "t",
+	Until:     "until",
+	Count:     "count",
+	Series:    "series",
 }
 
 func Test_dueDateRecurrence(t *testing.T) {
@@ -74,6 +77,21 @@ func Test_dueDateRecurrence(t *testing.T) {
 			today:           "2022-05-05",
 			expectedDueDate: "2022-05-08",
 		},
+		"business day absolute recurrence": {
+			dueDate:         "2023-08-04",
+			recurrence:      "+2bd",
+			expectedDueDate: "2023-08-08",
+		},
+		"nth weekday of month recurrence": {
+			dueDate:         "2023-08-08",
+			recurrence:      "+1m@2tue",
+			expectedDueDate: "2023-09-12",
+		},
+		"end of month recurrence": {
+			dueDate:         "2023-01-31",
+			recurrence:      "+1m@eom",
+			expectedDueDate: "2023-02-28",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -295,3 +313,235 @@ func Test_PriorityIsReserved(t *testing.T) {
 	assert.True(t, list.GetLine(1).Done())
 	assert.Equal(t, todotxt.PrioB, list.GetLine(2).Priority())
 }
+
+func Test_RRuleRecurrence(t *testing.T) {
+	testCases := map[string]struct {
+		dueDate         string
+		recurrence      string
+		expectedDueDate string
+		today           string
+	}{
+		"weekly on multiple days": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+FREQ=WEEKLY;BYDAY=MO,TH",
+			expectedDueDate: "2023-08-03",
+		},
+		"every other week": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			expectedDueDate: "2023-08-14",
+		},
+		"last friday of the month": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+FREQ=MONTHLY;BYDAY=-1FR",
+			expectedDueDate: "2023-08-25",
+		},
+		"last day of the month": {
+			dueDate:         "2023-01-31",
+			recurrence:      "+FREQ=MONTHLY;BYMONTHDAY=-1",
+			expectedDueDate: "2023-02-28",
+		},
+		"monthly skips months without the day": {
+			dueDate:         "2023-01-31",
+			recurrence:      "+FREQ=MONTHLY",
+			expectedDueDate: "2023-03-31",
+		},
+		"fourth thursday of november": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH",
+			expectedDueDate: "2023-11-23",
+		},
+		"every third day": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+FREQ=DAILY;INTERVAL=3",
+			expectedDueDate: "2023-08-04",
+		},
+		"every tenth year": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+FREQ=YEARLY;INTERVAL=10",
+			expectedDueDate: "2033-08-01",
+		},
+		"leap day with a large interval": {
+			dueDate:         "2024-02-29",
+			recurrence:      "+FREQ=YEARLY;INTERVAL=176",
+			expectedDueDate: "2376-02-29",
+		},
+		"case insensitive": {
+			dueDate:         "2023-08-01",
+			recurrence:      "+freq=weekly;byday=mo",
+			expectedDueDate: "2023-08-07",
+		},
+		"relative to the completion date": {
+			dueDate:         "2023-08-01",
+			recurrence:      "FREQ=WEEKLY;BYDAY=FR",
+			today:           "2022-05-05",
+			expectedDueDate: "2022-05-06",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var nowFunc func() time.Time
+			if len(tc.today) > 0 {
+				nowFunc = func() time.Time {
+					t, _ := time.Parse(time.DateOnly, tc.today)
+					return t
+				}
+			}
+			recurrentItem := todotxt.MustBuildItem(
+				todotxt.WithDescription(fmt.Sprintf("A recurrent item rec:%s due:%s", tc.recurrence, tc.dueDate)),
+				todotxt.WithNowFunc(nowFunc),
+			)
+			list := todotxt.ListOf(recurrentItem)
+			list.AddHook(hook.NewRecurrence(defaultTags, hook.WithNowFunc(nowFunc)))
+
+			err := recurrentItem.Complete()
+
+			assert.Nil(t, err)
+			assert.Equal(t, 2, list.Len())
+			assert.Equal(t, fmt.Sprintf("A recurrent item rec:%s due:%s", tc.recurrence, tc.expectedDueDate), list.GetLine(2).Description())
+		})
+	}
+}
+
+func Test_RRuleKeepsTheDistanceBetweenThresholdAndDueDate(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(
+		todotxt.WithDescription("A recurrent item rec:+FREQ=WEEKLY;BYDAY=TU t:2023-07-30 due:2023-08-01"),
+	)
+	list := todotxt.ListOf(recurrentItem)
+	list.AddHook(hook.NewRecurrence(defaultTags))
+
+	assert.Nil(t, recurrentItem.Complete())
+
+	assert.Equal(t, "A recurrent item rec:+FREQ=WEEKLY;BYDAY=TU t:2023-08-06 due:2023-08-08", list.GetLine(2).Description())
+}
+
+func Test_RecurrenceEndsAfterCount(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(
+		todotxt.WithDescription("A recurrent item rec:+1w due:2023-08-01 count:2"),
+	)
+	list := todotxt.ListOf(recurrentItem)
+	list.AddHook(hook.NewRecurrence(defaultTags))
+
+	assert.Nil(t, recurrentItem.Complete())
+	assert.Equal(t, 2, list.Len())
+	assert.Equal(t, "A recurrent item rec:+1w due:2023-08-08 count:1", list.GetLine(2).Description())
+
+	assert.Nil(t, list.GetLine(2).Complete())
+	assert.Equal(t, 2, list.Len())
+}
+
+func Test_RecurrenceEndsAfterUntil(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(
+		todotxt.WithDescription("A recurrent item rec:+1w due:2023-08-01 until:2023-08-10"),
+	)
+	list := todotxt.ListOf(recurrentItem)
+	list.AddHook(hook.NewRecurrence(defaultTags))
+
+	assert.Nil(t, recurrentItem.Complete())
+	assert.Equal(t, 2, list.Len())
+	assert.Equal(t, "A recurrent item rec:+1w due:2023-08-08 until:2023-08-10", list.GetLine(2).Description())
+
+	assert.Nil(t, list.GetLine(2).Complete())
+	assert.Equal(t, 2, list.Len())
+}
+
+func Test_MalformedRecurrenceRulesAreRejected(t *testing.T) {
+	for _, desc := range []string{
+		"rec:FREQ=HOURLY due:2023-08-01",
+		"rec:FREQ=WEEKLY;BYDAY=XX due:2023-08-01",
+		"rec:FREQ=WEEKLY;INTERVAL=0 due:2023-08-01",
+		"rec:FREQ=DAILY;INTERVAL=1000000000 due:2023-08-01",
+		"rec:FREQ=WEEKLY;COUNT=3 due:2023-08-01",
+		"rec:FREQ=WEEKLY;BYDAY=2MO due:2023-08-01",
+		"rec:FREQ=WEEKLY;BYMONTHDAY=1 due:2023-08-01",
+		"rec:FREQ=MONTHLY;BYMONTHDAY=32 due:2023-08-01",
+		"rec:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30 due:2023-08-01",
+		"rec:INTERVAL=2;BYDAY=MO due:2023-08-01",
+		"rec:+1w due:2023-08-01 count:0",
+		"rec:+1w due:2023-08-01 until:someday",
+	} {
+		list := todotxt.ListOf()
+		list.AddHook(hook.NewRecurrence(defaultTags))
+
+		err := list.Add(todotxt.MustBuildItem(todotxt.WithDescription(desc)))
+
+		assert.Error(t, err, desc)
+	}
+}
+
+func Test_SyncSpawnsMissedAndUpcomingOccurrences(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(todotxt.WithDescription("A chore rec:+1d due:2023-08-01 series:abc"))
+	relativeItem := todotxt.MustBuildItem(todotxt.WithDescription("A relative chore rec:1d due:2023-08-01"))
+	list := todotxt.ListOf(recurrentItem, relativeItem)
+	recurrence := hook.NewRecurrence(defaultTags)
+	list.AddHook(recurrence)
+
+	spawned, err := recurrence.Sync(list, time.Date(2023, 8, 4, 0, 0, 0, 0, time.UTC))
+
+	assert.Nil(t, err)
+	assert.Len(t, spawned, 3)
+	assert.Equal(t, 5, list.Len())
+	assert.Equal(t, "A chore rec:+1d due:2023-08-02 series:abc", list.GetLine(3).Description())
+	assert.Equal(t, "A chore rec:+1d due:2023-08-04 series:abc", list.GetLine(5).Description())
+
+	spawned, err = recurrence.Sync(list, time.Date(2023, 8, 4, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Len(t, spawned, 0)
+}
+
+func Test_SyncAssignsSeriesIds(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(todotxt.WithDescription("A chore rec:+1w due:2023-08-01"))
+	doneItem := todotxt.MustBuildItem(todotxt.WithDescription("A chore rec:+1w due:2023-07-25"), todotxt.WithDone(true))
+	list := todotxt.ListOf(recurrentItem, doneItem)
+	recurrence := hook.NewRecurrence(defaultTags)
+	list.AddHook(recurrence)
+
+	spawned, err := recurrence.Sync(list, time.Date(2023, 8, 8, 0, 0, 0, 0, time.UTC))
+
+	assert.Nil(t, err)
+	assert.Len(t, spawned, 1)
+	series := recurrentItem.Tags()["series"]
+	assert.Len(t, series, 1)
+	assert.Equal(t, series, spawned[0].Tags()["series"])
+	assert.Empty(t, doneItem.Tags()["series"])
+}
+
+func Test_CompletingDoesNotDuplicateSyncedOccurrences(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(todotxt.WithDescription("A chore rec:+1d due:2023-08-01 series:abc"))
+	list := todotxt.ListOf(recurrentItem)
+	recurrence := hook.NewRecurrence(defaultTags)
+	list.AddHook(recurrence)
+	_, err := recurrence.Sync(list, time.Date(2023, 8, 2, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+
+	assert.Nil(t, recurrentItem.Complete())
+
+	assert.Equal(t, 2, list.Len())
+}
+
+func Test_SyncRespectsTheEndOfTheRecurrence(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(todotxt.WithDescription("A chore rec:+1d due:2023-08-01 count:3 series:abc"))
+	list := todotxt.ListOf(recurrentItem)
+	recurrence := hook.NewRecurrence(defaultTags)
+	list.AddHook(recurrence)
+
+	spawned, err := recurrence.Sync(list, time.Date(2023, 8, 10, 0, 0, 0, 0, time.UTC))
+
+	assert.Nil(t, err)
+	assert.Len(t, spawned, 2)
+	assert.Equal(t, "A chore rec:+1d due:2023-08-03 count:1 series:abc", list.GetLine(3).Description())
+}
+
+func Test_SkipSpawnsTheNextOccurrenceWithoutCompleting(t *testing.T) {
+	recurrentItem := todotxt.MustBuildItem(todotxt.WithDescription("A chore rec:+1w due:2023-08-01"))
+	list := todotxt.ListOf(recurrentItem)
+	recurrence := hook.NewRecurrence(defaultTags)
+	list.AddHook(recurrence)
+
+	assert.Nil(t, recurrence.Skip(list, recurrentItem))
+
+	assert.Equal(t, 1, list.Len())
+	assert.Equal(t, "A chore rec:+1w due:2023-08-08", list.Tasks()[0].Description())
+	assert.False(t, list.Tasks()[0].Done())
+}
@@ -0,0 +1,250 @@
+package hook
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type frequency int
+
+const (
+	daily frequency = iota
+	weekly
+	monthly
+	yearly
+)
+
+var frequencyLookup = map[string]frequency{
+	"DAILY":   daily,
+	"WEEKLY":  weekly,
+	"MONTHLY": monthly,
+	"YEARLY":  yearly,
+}
+
+var rruleWeekdayLookup = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// maxRRuleSearchYears bounds the search for the next occurrence of a rule.
+// Eight years are enough to find the next February 29th.
+const maxRRuleSearchYears = 8
+
+// maxRRuleInterval is the largest INTERVAL accepted in a rule
+const maxRRuleInterval = 1000
+
+// weekdayNum is an element of BYDAY. If n is not 0 it refers to the n-th occurrence of
+// the weekday within the month (counted from the end if negative).
+type weekdayNum struct {
+	n       int
+	weekday time.Weekday
+}
+
+// rrule is the subset of an iCalendar (RFC 5545) recurrence rule that makes sense for tasks:
+// FREQ, INTERVAL, BYDAY, BYMONTHDAY and BYMONTH. The end of a recurrence is defined by tags instead of UNTIL and COUNT.
+type rrule struct {
+	freq       frequency
+	interval   int
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []time.Month
+}
+
+func isRRule(rec string) bool {
+	return strings.Contains(strings.ToUpper(rec), "FREQ=")
+}
+
+func parseRRule(rule string) (rrule, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(rule), "RRULE:")
+	r := rrule{interval: 1}
+	freqSet := false
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return rrule{}, fmt.Errorf("invalid rule part \"%s\": expected KEY=VALUE", part)
+		}
+		var err error
+		switch key {
+		case "FREQ":
+			if r.freq, ok = frequencyLookup[value]; !ok {
+				return rrule{}, fmt.Errorf("unsupported frequency %s: must be one of DAILY, WEEKLY, MONTHLY or YEARLY", value)
+			}
+			freqSet = true
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(value); err != nil || r.interval < 1 || r.interval > maxRRuleInterval {
+				return rrule{}, fmt.Errorf("invalid interval %s: must be an integer between 1 and %d", value, maxRRuleInterval)
+			}
+		case "BYDAY":
+			if r.byDay, err = parseByDay(value); err != nil {
+				return rrule{}, err
+			}
+		case "BYMONTHDAY":
+			if r.byMonthDay, err = parseIntList(value, -31, 31); err != nil {
+				return rrule{}, fmt.Errorf("invalid BYMONTHDAY: %w", err)
+			}
+		case "BYMONTH":
+			months, err := parseIntList(value, 1, 12)
+			if err != nil {
+				return rrule{}, fmt.Errorf("invalid BYMONTH: %w", err)
+			}
+			for _, m := range months {
+				r.byMonth = append(r.byMonth, time.Month(m))
+			}
+		case "UNTIL", "COUNT":
+			return rrule{}, fmt.Errorf("%s is not supported in the rule. Use the until or count tag instead", key)
+		default:
+			return rrule{}, fmt.Errorf("unsupported rule part %s", key)
+		}
+	}
+	if !freqSet {
+		return rrule{}, fmt.Errorf("the rule is missing FREQ")
+	}
+	if r.freq == weekly && len(r.byMonthDay) > 0 {
+		return rrule{}, fmt.Errorf("BYMONTHDAY can not be used with FREQ=WEEKLY")
+	}
+	ordinals := slices.ContainsFunc(r.byDay, func(w weekdayNum) bool { return w.n != 0 })
+	if ordinals && r.freq != monthly && (r.freq != yearly || len(r.byMonth) == 0) {
+		return rrule{}, fmt.Errorf("BYDAY with an ordinal (e.g. 2TU) requires FREQ=MONTHLY or FREQ=YEARLY with BYMONTH")
+	}
+	return r, nil
+}
+
+func parseByDay(value string) ([]weekdayNum, error) {
+	days := make([]weekdayNum, 0)
+	for _, d := range strings.Split(value, ",") {
+		if len(d) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %s", d)
+		}
+		weekday, ok := rruleWeekdayLookup[d[len(d)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %s in BYDAY: must be one of MO, TU, WE, TH, FR, SA or SU", d[len(d)-2:])
+		}
+		n := 0
+		if ordinal := d[:len(d)-2]; ordinal != "" {
+			var err error
+			if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid ordinal %s in BYDAY: must be between -5 and 5 (excluding 0)", ordinal)
+			}
+		}
+		days = append(days, weekdayNum{n, weekday})
+	}
+	return days, nil
+}
+
+func parseIntList(value string, minValue, maxValue int) ([]int, error) {
+	values := make([]int, 0)
+	for _, v := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(v)
+		if err != nil || i == 0 || i < minValue || i > maxValue {
+			return nil, fmt.Errorf("%s must be a non-zero integer between %d and %d", v, minValue, maxValue)
+		}
+		values = append(values, i)
+	}
+	return values, nil
+}
+
+// next returns the first occurrence of the rule after t. The interval is counted from t.
+// The second return value is false if there is no occurrence within the next years.
+// Periods outside of the interval are skipped as a whole, so only the days of about
+// maxRRuleSearchYears years are checked, no matter how large the interval is.
+func (r rrule) next(t time.Time) (time.Time, bool) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := start.AddDate(maxRRuleSearchYears*r.interval, 0, 0)
+	for d := start.AddDate(0, 0, 1); !d.After(end); {
+		if periods := r.periods(start, d); periods%r.interval != 0 {
+			d = r.periodStart(start, periods+r.interval-periods%r.interval)
+			continue
+		}
+		if r.matches(start, d) {
+			return d, true
+		}
+		d = d.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// periods returns the number of whole periods (days, weeks, months or years) between start and d
+func (r rrule) periods(start, d time.Time) int {
+	switch r.freq {
+	case weekly:
+		return int(mondayOf(d).Sub(mondayOf(start)).Hours() / (24 * 7))
+	case monthly:
+		return (d.Year()-start.Year())*12 + int(d.Month()-start.Month())
+	case yearly:
+		return d.Year() - start.Year()
+	}
+	return int(d.Sub(start).Hours() / 24)
+}
+
+// periodStart returns the first day of the n-th period after the one containing start
+func (r rrule) periodStart(start time.Time, n int) time.Time {
+	switch r.freq {
+	case weekly:
+		return mondayOf(start).AddDate(0, 0, 7*n)
+	case monthly:
+		return time.Date(start.Year(), start.Month()+time.Month(n), 1, 0, 0, 0, 0, start.Location())
+	case yearly:
+		return time.Date(start.Year()+n, time.January, 1, 0, 0, 0, 0, start.Location())
+	}
+	return start.AddDate(0, 0, n)
+}
+
+func (r rrule) matches(start, d time.Time) bool {
+	byMonth, byMonthDay, byDay := r.byMonth, r.byMonthDay, r.byDay
+	// Like in RFC 5545 missing BY* parts are derived from the start
+	switch {
+	case r.freq == weekly && len(byDay) == 0:
+		byDay = []weekdayNum{{0, start.Weekday()}}
+	case r.freq == monthly && len(byDay) == 0 && len(byMonthDay) == 0:
+		byMonthDay = []int{start.Day()}
+	case r.freq == yearly && len(byDay) == 0 && len(byMonthDay) == 0:
+		byMonthDay = []int{start.Day()}
+		if len(byMonth) == 0 {
+			byMonth = []time.Month{start.Month()}
+		}
+	}
+	if len(byMonth) > 0 && !slices.Contains(byMonth, d.Month()) {
+		return false
+	}
+	if len(byMonthDay) > 0 && !slices.ContainsFunc(byMonthDay, func(md int) bool { return isMonthDay(d, md) }) {
+		return false
+	}
+	if len(byDay) > 0 && !slices.ContainsFunc(byDay, func(w weekdayNum) bool { return isWeekdayNum(d, w) }) {
+		return false
+	}
+	return true
+}
+
+func isMonthDay(d time.Time, monthDay int) bool {
+	if monthDay > 0 {
+		return d.Day() == monthDay
+	}
+	daysInMonth := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return d.Day() == daysInMonth+monthDay+1
+}
+
+func isWeekdayNum(d time.Time, w weekdayNum) bool {
+	if d.Weekday() != w.weekday {
+		return false
+	}
+	switch {
+	case w.n > 0:
+		return (d.Day()-1)/7+1 == w.n
+	case w.n < 0:
+		daysInMonth := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		return (daysInMonth-d.Day())/7+1 == -w.n
+	}
+	return true
+}
+
+func mondayOf(d time.Time) time.Time {
+	return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+}
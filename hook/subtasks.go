@@ -0,0 +1,128 @@
+package hook
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+// The ways a parent reacts to the completion of its subtasks
+const (
+	ParentCompletionAuto   = "auto"
+	ParentCompletionStrict = "strict"
+)
+
+// Subtasks validates the subtask tag, which refers to the id of the parent of a task.
+// Parents must exist when the reference is added and the hierarchy must not contain cycles.
+type Subtasks struct {
+	Tag   string
+	IdTag string
+	// ParentCompletion is one of "", ParentCompletionAuto or ParentCompletionStrict.
+	// Auto completes a parent as soon as all of its subtasks are done, strict refuses to complete a parent with open subtasks.
+	ParentCompletion string
+}
+
+func NewSubtasks(tag string, idTag string) *Subtasks {
+	return &Subtasks{
+		Tag:   tag,
+		IdTag: idTag,
+	}
+}
+
+func (s Subtasks) OnMod(list *todotxt.List, event todotxt.ModEvent) error {
+	if event.Current == nil {
+		return nil
+	}
+	if parentId := s.parentIdOf(event.Current); parentId != "" && (event.Previous == nil || parentId != s.parentIdOf(event.Previous)) {
+		if parentId == s.idOf(event.Current) {
+			return fmt.Errorf("task can not be its own parent (%s:%s)", s.Tag, parentId)
+		}
+		if s.byId(list, parentId) == nil {
+			return fmt.Errorf("parent task id %s does not exist", parentId)
+		}
+	}
+	if event.IsCompleteEvent() {
+		if err := s.onComplete(list, event.Current); err != nil {
+			return err
+		}
+	}
+	return s.OnValidate(list, todotxt.ValidationEvent{Item: event.Current})
+}
+
+func (s Subtasks) OnValidate(list *todotxt.List, event todotxt.ValidationEvent) error {
+	id := s.idOf(event.Item)
+	if id == "" {
+		return nil
+	}
+	path := []string{id}
+	for current := event.Item; ; {
+		parentId := s.parentIdOf(current)
+		if parentId == "" {
+			return nil
+		}
+		path = append(path, parentId)
+		if parentId == id {
+			return fmt.Errorf("subtask cycle detected: %s", strings.Join(path, " -> "))
+		}
+		if current = s.byId(list, parentId); current == nil || len(path) > list.Len()+1 {
+			return nil
+		}
+	}
+}
+
+func (s Subtasks) onComplete(list *todotxt.List, item *todotxt.Item) error {
+	switch s.ParentCompletion {
+	case ParentCompletionStrict:
+		if open := s.openChildren(list, item); open > 0 {
+			return fmt.Errorf("task can not be completed, because it has %d open subtask(s)", open)
+		}
+	case ParentCompletionAuto:
+		// The parent is completed after the hooks, so that its completion runs the hooks as well
+		// (e.g. recurrence or dependencies). Its own parent is handled by that completion.
+		if parent := s.byId(list, s.parentIdOf(item)); parent != nil && !parent.Done() && s.openChildren(list, parent) == 0 {
+			return list.AfterHooks(parent.Complete)
+		}
+	}
+	return nil
+}
+
+func (s Subtasks) openChildren(list *todotxt.List, parent *todotxt.Item) int {
+	id := s.idOf(parent)
+	if id == "" {
+		return 0
+	}
+	open := 0
+	for _, t := range list.Tasks() {
+		if t != parent && !t.Done() && s.parentIdOf(t) == id {
+			open++
+		}
+	}
+	return open
+}
+
+func (s Subtasks) byId(list *todotxt.List, id string) *todotxt.Item {
+	if id == "" {
+		return nil
+	}
+	for _, t := range list.Tasks() {
+		if s.idOf(t) == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s Subtasks) parentIdOf(item *todotxt.Item) string {
+	if values := item.TagValues(s.Tag); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (s Subtasks) idOf(item *todotxt.Item) string {
+	if values := item.TagValues(s.IdTag); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
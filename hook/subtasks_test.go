@@ -0,0 +1,83 @@
+package hook_test
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/hook"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SubtasksRejectUnknownParents(t *testing.T) {
+	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("release id:a")))
+	list.AddHook(hook.NewSubtasks("parent", "id"))
+
+	assert.Error(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("write code id:b parent:x"))))
+	assert.Error(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("write code id:b parent:b"))))
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("write code id:b parent:a"))))
+}
+
+func Test_SubtasksRejectCycles(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("release id:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write code id:b parent:a")),
+	)
+	list.AddHook(hook.NewSubtasks("parent", "id"))
+
+	err := list.GetLine(1).SetTag("parent", "b")
+
+	assert.ErrorContains(t, err, "a -> b -> a")
+	assert.Empty(t, list.GetLine(1).Tags()["parent"])
+}
+
+func Test_SubtasksAutoCompleteParents(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("release id:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write code id:b parent:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write tests id:c parent:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write unit tests id:d parent:c")),
+	)
+	subtasks := hook.NewSubtasks("parent", "id")
+	subtasks.ParentCompletion = hook.ParentCompletionAuto
+	list.AddHook(subtasks)
+
+	assert.Nil(t, list.GetLine(2).Complete())
+	assert.False(t, list.GetLine(1).Done())
+
+	assert.Nil(t, list.GetLine(4).Complete())
+	assert.True(t, list.GetLine(3).Done())
+	assert.True(t, list.GetLine(1).Done())
+}
+
+func Test_AutoCompletedParentsRunTheOtherHooks(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("weekly review id:a rec:+1w due:2022-02-02")),
+		todotxt.MustBuildItem(todotxt.WithDescription("clean inbox id:b parent:a")),
+	)
+	subtasks := hook.NewSubtasks("parent", "id")
+	subtasks.ParentCompletion = hook.ParentCompletionAuto
+	list.AddHook(subtasks)
+	list.AddHook(hook.NewRecurrence(defaultTags))
+
+	assert.Nil(t, list.GetLine(2).Complete())
+
+	assert.True(t, list.GetLine(1).Done())
+	assert.Equal(t, 3, list.Len())
+	assert.Contains(t, list.GetLine(3).Description(), "due:2022-02-09")
+}
+
+func Test_SubtasksStrictRefusesToCompleteParentsWithOpenChildren(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("release id:a")),
+		todotxt.MustBuildItem(todotxt.WithDescription("write code id:b parent:a")),
+	)
+	subtasks := hook.NewSubtasks("parent", "id")
+	subtasks.ParentCompletion = hook.ParentCompletionStrict
+	list.AddHook(subtasks)
+
+	assert.ErrorContains(t, list.GetLine(1).Complete(), "1 open subtask(s)")
+	assert.False(t, list.GetLine(1).Done())
+
+	assert.Nil(t, list.GetLine(2).Complete())
+	assert.Nil(t, list.GetLine(1).Complete())
+}
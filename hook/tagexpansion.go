@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -71,6 +72,8 @@ func (t TagExpansion) expandTag(list *todotxt.List, typ qselect.DType, tag strin
 	switch typ {
 	case qselect.QDate:
 		return i.SetTag(tag, t.expandDate(tagValue))
+	case qselect.QDateTime:
+		return i.SetTag(tag, t.expandDateTime(tagValue))
 	case qselect.QInt:
 		return i.SetTag(tag, t.expandInt(list, i, tag, tagValue))
 	}
@@ -82,44 +85,99 @@ func (t TagExpansion) expandDate(v string) string {
 		// This is already a proper date
 		return v
 	}
-	var base time.Time
 	var remainingValue string = strings.ToLower(v)
 	possibleBase := t.guessBase(remainingValue)
-	switch {
-	case t.isWeekday(possibleBase):
-		remainingValue = strings.TrimPrefix(remainingValue, possibleBase)
-		base = t.findNext(weekdayLookup[possibleBase])
-	case possibleBase == "tomorrow":
-		base = t.today().Add(24 * time.Hour)
-		remainingValue = strings.TrimPrefix(remainingValue, possibleBase)
-	case possibleBase == "yesterday":
-		base = t.today().Add(-24 * time.Hour)
+	base, ok := t.dayBase(possibleBase)
+	if ok {
 		remainingValue = strings.TrimPrefix(remainingValue, possibleBase)
-	case possibleBase == "today":
-		remainingValue = strings.TrimPrefix(remainingValue, possibleBase)
-		fallthrough
-	default:
+	} else {
 		base = t.today()
 	}
 	if len(strings.TrimSpace(remainingValue)) == 0 {
 		return base.Format(time.DateOnly)
 	}
-	if duration, err := qduration.Parse(remainingValue); err == nil {
-		// This is a duration
+	if duration, err := qduration.Parse(remainingValue); err == nil && !duration.IsSubDay() {
+		// This is a duration. Sub-day durations are left to validation, since a date can not hold them
 		return duration.AddTo(base).Format(time.DateOnly)
 	}
 	// Unknown expansion. Let validation handle that
 	return v
 }
 
+// dayBase returns the day described by base (today, tomorrow, yesterday or a weekday).
+func (t TagExpansion) dayBase(base string) (time.Time, bool) {
+	switch {
+	case t.isWeekday(base):
+		return t.findNext(weekdayLookup[base]), true
+	case base == "tomorrow":
+		return t.today().Add(24 * time.Hour), true
+	case base == "yesterday":
+		return t.today().Add(-24 * time.Hour), true
+	case base == "today":
+		return t.today(), true
+	}
+	return time.Time{}, false
+}
+
+var timeOfDayRegex = regexp.MustCompile(`^(.*?)([0-9]{1,2}):([0-9]{2})$`)
+
+// expandDateTime works like expandDate, but the base may be followed by a time of day (e.g. tomorrowT14:00 or 9:30).
+// If neither a day nor a time of day is given, the duration is relative to now.
+func (t TagExpansion) expandDateTime(v string) string {
+	if _, err := time.Parse(qselect.DateTimeLayout, v); err == nil {
+		// This is already a proper datetime
+		return v
+	}
+	var remainingValue string = strings.ToLower(v)
+	possibleBase := t.guessBase(remainingValue)
+	remainingValue = strings.TrimPrefix(remainingValue, possibleBase)
+	day, hasTimeOfDay, hour, minute := possibleBase, false, 0, 0
+	if m := timeOfDayRegex.FindStringSubmatch(possibleBase); m != nil {
+		day, hasTimeOfDay = m[1], true
+		hour, _ = strconv.Atoi(m[2])
+		minute, _ = strconv.Atoi(m[3])
+		if hour > 23 || minute > 59 {
+			return v
+		}
+		// The day and the time of day may be separated by a "T" like in the datetime format
+		if withoutSep := strings.TrimSuffix(day, "t"); withoutSep != day && (withoutSep == "" || t.isDay(withoutSep)) {
+			day = withoutSep
+		}
+	}
+	base, ok := t.dayBase(day)
+	switch {
+	case !ok && day != "":
+		// Unknown expansion. Let validation handle that
+		return v
+	case !ok && !hasTimeOfDay:
+		base = t.now()
+		base = time.Date(base.Year(), base.Month(), base.Day(), base.Hour(), base.Minute(), 0, 0, time.UTC)
+	case !ok:
+		base = t.today()
+	}
+	base = base.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	if len(strings.TrimSpace(remainingValue)) == 0 {
+		return base.Format(qselect.DateTimeLayout)
+	}
+	if duration, err := qduration.Parse(remainingValue); err == nil {
+		return duration.AddTo(base).Format(qselect.DateTimeLayout)
+	}
+	return v
+}
+
 func (t TagExpansion) guessBase(v string) string {
-	splitIdx := strings.IndexAny(v, "+-")
+	splitIdx := strings.IndexAny(v, "+-@")
 	if splitIdx == -1 {
 		return v
 	}
 	return v[:splitIdx]
 }
 
+func (t TagExpansion) isDay(v string) bool {
+	_, ok := t.dayBase(v)
+	return ok
+}
+
 func (t TagExpansion) isWeekday(v string) bool {
 	_, ok := weekdayLookup[v]
 	return ok
@@ -274,6 +332,11 @@ func validateTag(typ qselect.DType, tag string, values []string) error {
 			if err != nil {
 				validationErrors = append(validationErrors, fmt.Errorf("tag \"%s\" of item violates date constraint: %w", tag, err))
 			}
+		case qselect.QDateTime:
+			_, err := time.Parse(qselect.DateTimeLayout, v)
+			if err != nil {
+				validationErrors = append(validationErrors, fmt.Errorf("tag \"%s\" of item violates datetime constraint: %w", tag, err))
+			}
 		case qselect.QDuration:
 			_, err := qduration.Parse(v)
 			if err != nil {
@@ -176,6 +176,22 @@ func Test_DateExpansion(t *testing.T) {
 			description:       "due:MoNdAy",
 			expectedExpansion: "due:2022-02-07",
 		},
+		"business days": {
+			description:       "due:friday+1bd",
+			expectedExpansion: "due:2022-02-07",
+		},
+		"end of month": {
+			description:       "due:@eom",
+			expectedExpansion: "due:2022-02-28",
+		},
+		"end of month with base": {
+			description:       "due:tomorrow@eom",
+			expectedExpansion: "due:2022-02-28",
+		},
+		"nth weekday of next month": {
+			description:       "due:+1m@1mon",
+			expectedExpansion: "due:2022-03-07",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -198,6 +214,66 @@ func Test_DateExpansion(t *testing.T) {
 	}
 }
 
+func Test_DateTimeExpansion(t *testing.T) {
+	now := time.Date(2022, 2, 2, 10, 17, 42, 0, time.Local)
+	testCases := map[string]struct {
+		description       string
+		expectedExpansion string
+	}{
+		"datetime without expansion is left unchanged": {
+			description:       "at:2023-01-01T14:00",
+			expectedExpansion: "at:2023-01-01T14:00",
+		},
+		"duration is relative to now": {
+			description:       "at:+2h",
+			expectedExpansion: "at:2022-02-02T12:17",
+		},
+		"minutes": {
+			description:       "at:-30min",
+			expectedExpansion: "at:2022-02-02T09:47",
+		},
+		"day without time of day is midnight": {
+			description:       "at:tomorrow",
+			expectedExpansion: "at:2022-02-03T00:00",
+		},
+		"day with time of day": {
+			description:       "at:tomorrowT14:00",
+			expectedExpansion: "at:2022-02-03T14:00",
+		},
+		"weekday ending with t and time of day": {
+			description:       "at:sat9:30",
+			expectedExpansion: "at:2022-02-05T09:30",
+		},
+		"time of day only": {
+			description:       "at:16:45",
+			expectedExpansion: "at:2022-02-02T16:45",
+		},
+		"time of day with offset": {
+			description:       "at:fridayT8:00+90min",
+			expectedExpansion: "at:2022-02-04T09:30",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			item := todotxt.MustBuildItem(
+				todotxt.WithDescription("Hello World"),
+			)
+			list := todotxt.ListOf(item)
+			list.AddHook(hook.NewTagExpansionWithNowFunc(false, map[string]qselect.DType{
+				"at": qselect.QDateTime,
+			}, func() time.Time {
+				return now
+			}))
+
+			err := item.EditDescription(tc.description)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedExpansion, item.Description())
+		})
+	}
+}
+
 func Test_TagExpansionsIgnoresRemovalEvents(t *testing.T) {
 	item := todotxt.MustBuildItem(
 		todotxt.WithDescription("Hello World"),
@@ -225,6 +301,12 @@ func Test_TagExpansionsError(t *testing.T) {
 		"wrong expansion": {
 			description: "a wrong int someInt:expansion",
 		},
+		"invalid time of day": {
+			description: "an appointment at:tomorrowT25:00",
+		},
+		"hours can not be added to a date": {
+			description: "hello due:+12h",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -235,6 +317,7 @@ func Test_TagExpansionsError(t *testing.T) {
 			list := todotxt.ListOf(item)
 			list.AddHook(hook.NewTagExpansionWithNowFunc(false, map[string]qselect.DType{
 				"due":     qselect.QDate,
+				"at":      qselect.QDateTime,
 				"someInt": qselect.QInt,
 			}, func() time.Time {
 				return time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC)
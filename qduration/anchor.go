@@ -0,0 +1,95 @@
+package qduration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type anchorKind int
+
+const (
+	noAnchor anchorKind = iota
+	endOfMonth
+	nthWeekday
+)
+
+// lastOccurrence is used as n of a nthWeekday anchor to refer to the last occurrence in the month
+const lastOccurrence = -1
+
+// anchor moves a date to a fixed day within its month
+type anchor struct {
+	kind    anchorKind
+	n       int
+	weekday time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"mon":       time.Monday,
+	"monday":    time.Monday,
+	"tue":       time.Tuesday,
+	"tuesday":   time.Tuesday,
+	"wed":       time.Wednesday,
+	"wednesday": time.Wednesday,
+	"thu":       time.Thursday,
+	"thursday":  time.Thursday,
+	"fri":       time.Friday,
+	"friday":    time.Friday,
+	"sat":       time.Saturday,
+	"saturday":  time.Saturday,
+	"sun":       time.Sunday,
+	"sunday":    time.Sunday,
+}
+
+func parseAnchor(a string) (anchor, error) {
+	a = strings.ToLower(a)
+	if a == "eom" {
+		return anchor{kind: endOfMonth}, nil
+	}
+	var n int
+	var weekdayS string
+	switch {
+	case strings.HasPrefix(a, "last"):
+		n, weekdayS = lastOccurrence, strings.TrimPrefix(a, "last")
+	case len(a) > 0:
+		var err error
+		if n, err = strconv.Atoi(a[:1]); err != nil || n < 1 || n > 5 {
+			return anchor{}, fmt.Errorf("invalid anchor %s: expected eom, last<weekday> or <1-5><weekday>", a)
+		}
+		weekdayS = a[1:]
+	}
+	weekday, ok := weekdays[weekdayS]
+	if !ok {
+		return anchor{}, fmt.Errorf("invalid anchor %s: unknown weekday %s", a, weekdayS)
+	}
+	return anchor{kind: nthWeekday, n: n, weekday: weekday}, nil
+}
+
+func (a anchor) apply(t time.Time) time.Time {
+	switch a.kind {
+	case endOfMonth:
+		return withDay(t, daysIn(t))
+	case nthWeekday:
+		if a.n == lastOccurrence {
+			last := withDay(t, daysIn(t))
+			return withDay(t, last.Day()-(int(last.Weekday())-int(a.weekday)+7)%7)
+		}
+		first := withDay(t, 1)
+		day := 1 + (int(a.weekday)-int(first.Weekday())+7)%7 + (a.n-1)*7
+		if day > daysIn(t) {
+			// There is no fifth occurrence in this month, so take the last one
+			day -= 7
+		}
+		return withDay(t, day)
+	}
+	return t
+}
+
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func withDay(t time.Time, day int) time.Time {
+	return time.Date(t.Year(), t.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
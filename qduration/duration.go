@@ -1,6 +1,9 @@
 package qduration
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -13,31 +16,61 @@ const (
 	Week
 	Month
 	Year
+	Hour
+	Minute
+	BusinessDay
 )
 
 var unitMap = map[string]DurationUnit{
-	"d":      Day,
-	"days":   Day,
-	"w":      Week,
-	"weeks":  Week,
-	"m":      Month,
-	"months": Month,
-	"y":      Year,
-	"years":  Year,
+	"d":            Day,
+	"days":         Day,
+	"w":            Week,
+	"weeks":        Week,
+	"m":            Month,
+	"months":       Month,
+	"y":            Year,
+	"years":        Year,
+	"h":            Hour,
+	"hours":        Hour,
+	"min":          Minute,
+	"minutes":      Minute,
+	"bd":           BusinessDay,
+	"businessdays": BusinessDay,
 }
 
 type Duration struct {
-	span int
-	unit DurationUnit
+	span   int
+	unit   DurationUnit
+	anchor anchor
 }
 
 func New(span int, unit DurationUnit) Duration {
 	return Duration{
-		span, unit,
+		span: span, unit: unit,
 	}
 }
 
+// AddTo adds the duration to t. If the duration has an anchor (e.g. @eom),
+// the result is moved to the anchor within the month of the result.
 func (d Duration) AddTo(t time.Time) time.Time {
+	return d.anchor.apply(d.addTo(d.anchorBase(t)))
+}
+
+// SubFrom subtracts the duration from t. Like for AddTo the anchor is applied afterwards.
+func (d Duration) SubFrom(t time.Time) time.Time {
+	return d.anchor.apply(d.subFrom(d.anchorBase(t)))
+}
+
+// anchorBase moves t to the first of the month if the anchor determines the day anyway.
+// This prevents month arithmetic from overflowing into the next month (e.g. 2023-01-31 + 1m@eom).
+func (d Duration) anchorBase(t time.Time) time.Time {
+	if d.anchor.kind != noAnchor && (d.unit == Month || d.unit == Year) {
+		return withDay(t, 1)
+	}
+	return t
+}
+
+func (d Duration) addTo(t time.Time) time.Time {
 	switch d.unit {
 	case Day:
 		return time.Date(t.Year(), t.Month(), t.Day()+d.span, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
@@ -47,12 +80,18 @@ func (d Duration) AddTo(t time.Time) time.Time {
 		return time.Date(t.Year(), t.Month()+time.Month(d.span), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 	case Year:
 		return time.Date(t.Year()+d.span, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	case Hour:
+		return t.Add(time.Duration(d.span) * time.Hour)
+	case Minute:
+		return t.Add(time.Duration(d.span) * time.Minute)
+	case BusinessDay:
+		return addBusinessDays(t, d.span)
 	default:
 		panic("unknown unit")
 	}
 }
 
-func (d Duration) SubFrom(t time.Time) time.Time {
+func (d Duration) subFrom(t time.Time) time.Time {
 	switch d.unit {
 	case Day:
 		return time.Date(t.Year(), t.Month(), t.Day()-d.span, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
@@ -62,6 +101,12 @@ func (d Duration) SubFrom(t time.Time) time.Time {
 		return time.Date(t.Year(), t.Month()-time.Month(d.span), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 	case Year:
 		return time.Date(t.Year()-d.span, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	case Hour:
+		return t.Add(-time.Duration(d.span) * time.Hour)
+	case Minute:
+		return t.Add(-time.Duration(d.span) * time.Minute)
+	case BusinessDay:
+		return addBusinessDays(t, -d.span)
 	default:
 		panic("unknown unit")
 	}
@@ -69,11 +114,13 @@ func (d Duration) SubFrom(t time.Time) time.Time {
 
 func (d Duration) Abs() Duration {
 	if d.span < 0 {
-		return Duration{-d.span, d.unit}
+		return Duration{-d.span, d.unit, d.anchor}
 	}
-	return Duration{d.span, d.unit}
+	return d
 }
 
+// Days returns the approximate length of the duration in days. Months have 30 days and years 365 days.
+// Durations of hours or minutes are rounded up to whole days, so that only a span of 0 has 0 days.
 func (d Duration) Days() int {
 	switch d.unit {
 	case Day:
@@ -84,18 +131,67 @@ func (d Duration) Days() int {
 		return d.span * 30
 	case Year:
 		return d.span * 365
+	case Hour:
+		return roundUp(d.span, 24)
+	case Minute:
+		return roundUp(d.span, 24*60)
+	case BusinessDay:
+		return d.span * 7 / 5
 	}
 	return d.span
 }
 
+// IsSubDay reports whether the duration is measured in hours or minutes
+func (d Duration) IsSubDay() bool {
+	return d.unit == Hour || d.unit == Minute
+}
+
+// roundUp divides a by b and rounds away from zero
+func roundUp(a, b int) int {
+	if a < 0 {
+		return -roundUp(-a, b)
+	}
+	return (a + b - 1) / b
+}
+
+// Minutes returns the approximate length of the duration in minutes.
+// Like for Days, months have 30 days and years 365 days.
+func (d Duration) Minutes() int {
+	switch d.unit {
+	case Hour:
+		return d.span * 60
+	case Minute:
+		return d.span
+	}
+	return d.Days() * 24 * 60
+}
+
+// Parse parses a duration like "+3d", "-2w", "5bd" or "1m@eom".
+// The optional anchor after the "@" is one of eom (end of month), <n><weekday> (e.g. 2tue for the second Tuesday
+// of the month) or last<weekday>. A duration consisting only of an anchor (e.g. "@eom") has a span of 0.
 func Parse(durationS string) (Duration, error) {
+	var a anchor
+	if anchorIdx := strings.Index(durationS, "@"); anchorIdx != -1 {
+		var err error
+		if a, err = parseAnchor(durationS[anchorIdx+1:]); err != nil {
+			return Duration{}, err
+		}
+		durationS = durationS[:anchorIdx]
+		if durationS == "" {
+			return Duration{0, Day, a}, nil
+		}
+	}
 	var unit DurationUnit
+	longestSuffix := ""
 	for u := range unitMap {
-		if strings.HasSuffix(durationS, u) {
-			unit = unitMap[u]
-			durationS = strings.TrimSuffix(durationS, u)
+		if strings.HasSuffix(durationS, u) && len(u) > len(longestSuffix) {
+			longestSuffix = u
 		}
 	}
+	if longestSuffix != "" {
+		unit = unitMap[longestSuffix]
+		durationS = strings.TrimSuffix(durationS, longestSuffix)
+	}
 	var sign int
 	switch {
 	case strings.HasPrefix(durationS, "-"):
@@ -108,11 +204,68 @@ func Parse(durationS string) (Duration, error) {
 		sign = 1
 	}
 	if len(durationS) == 0 {
-		return Duration{sign, unit}, nil
+		return Duration{sign, unit, a}, nil
 	}
 	span, err := strconv.Atoi(durationS)
 	if err != nil {
 		return Duration{}, err
 	}
-	return Duration{sign * span, unit}, nil
+	return Duration{sign * span, unit, a}, nil
+}
+
+var holidays = make(map[time.Time]struct{})
+
+// SetHolidays replaces the days that are skipped by business day durations (in addition to weekends).
+// It is not safe to call SetHolidays concurrently with AddTo or SubFrom.
+func SetHolidays(days ...time.Time) {
+	holidays = make(map[time.Time]struct{}, len(days))
+	for _, d := range days {
+		holidays[truncateToDay(d)] = struct{}{}
+	}
+}
+
+// ReadHolidays reads a holiday list with one date (YYYY-MM-DD) per line.
+// Empty lines and lines starting with # are ignored.
+func ReadHolidays(r io.Reader) ([]time.Time, error) {
+	days := make([]time.Time, 0)
+	scanner := bufio.NewScanner(r)
+	for lineNr := 1; scanner.Scan(); lineNr++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		day, err := time.Parse(time.DateOnly, line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday in line %d: %w", lineNr, err)
+		}
+		days = append(days, day)
+	}
+	return days, scanner.Err()
+}
+
+// IsBusinessDay returns false if t is on a weekend or a holiday.
+func IsBusinessDay(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := holidays[truncateToDay(t)]
+	return !holiday
+}
+
+func addBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day()+step, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+		if IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
@@ -1,7 +1,9 @@
 package qduration_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Fabian-G/quest/qduration"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +30,26 @@ func Test_DurationParse(t *testing.T) {
 			durationString: "-100years",
 			duration:       qduration.New(-100, qduration.Year),
 		},
+		"hours": {
+			durationString: "+2h",
+			duration:       qduration.New(2, qduration.Hour),
+		},
+		"minutes": {
+			durationString: "-30min",
+			duration:       qduration.New(-30, qduration.Minute),
+		},
+		"minutes long": {
+			durationString: "45minutes",
+			duration:       qduration.New(45, qduration.Minute),
+		},
+		"business days": {
+			durationString: "5bd",
+			duration:       qduration.New(5, qduration.BusinessDay),
+		},
+		"business days long": {
+			durationString: "-2businessdays",
+			duration:       qduration.New(-2, qduration.BusinessDay),
+		},
 	}
 
 	for name, tc := range testCases {
@@ -38,3 +60,114 @@ func Test_DurationParse(t *testing.T) {
 		})
 	}
 }
+
+func Test_HoursAndMinutesAreAddedToTheTimeOfDay(t *testing.T) {
+	base := time.Date(2023, 1, 31, 23, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2023, 2, 1, 1, 30, 0, 0, time.UTC), qduration.New(2, qduration.Hour).AddTo(base))
+	assert.Equal(t, time.Date(2023, 1, 31, 23, 0, 0, 0, time.UTC), qduration.New(30, qduration.Minute).SubFrom(base))
+	assert.Equal(t, 90, qduration.New(90, qduration.Minute).Minutes())
+	assert.Equal(t, 2*24*60, qduration.New(2, qduration.Day).Minutes())
+}
+
+func Test_SubDayDurationsAreRoundedUpToWholeDays(t *testing.T) {
+	assert.Equal(t, 1, qduration.New(2, qduration.Hour).Days())
+	assert.Equal(t, -1, qduration.New(-90, qduration.Minute).Days())
+	assert.Equal(t, 2, qduration.New(25, qduration.Hour).Days())
+	assert.Equal(t, 0, qduration.New(0, qduration.Minute).Days())
+}
+
+func Test_BusinessDaysSkipWeekendsAndHolidays(t *testing.T) {
+	defer qduration.SetHolidays()
+	friday := time.Date(2023, 12, 22, 0, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		holidays []time.Time
+		duration qduration.Duration
+		expected time.Time
+	}{
+		"over the weekend": {
+			duration: qduration.New(1, qduration.BusinessDay),
+			expected: time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC),
+		},
+		"over the weekend and holidays": {
+			holidays: []time.Time{time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC)},
+			duration: qduration.New(2, qduration.BusinessDay),
+			expected: time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC),
+		},
+		"backwards": {
+			duration: qduration.New(-5, qduration.BusinessDay),
+			expected: time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC),
+		},
+		"zero": {
+			duration: qduration.New(0, qduration.BusinessDay),
+			expected: friday,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			qduration.SetHolidays(tc.holidays...)
+			assert.Equal(t, tc.expected, tc.duration.AddTo(friday))
+			assert.Equal(t, friday, tc.duration.SubFrom(tc.expected))
+		})
+	}
+}
+
+func Test_AnchoredDurations(t *testing.T) {
+	base := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		durationString string
+		expected       time.Time
+	}{
+		"end of month": {
+			durationString: "@eom",
+			expected:       time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		"end of next month in a leap year": {
+			durationString: "+1m@eom",
+			expected:       time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"second tuesday of next month": {
+			durationString: "1m@2tue",
+			expected:       time.Date(2024, 2, 13, 0, 0, 0, 0, time.UTC),
+		},
+		"first monday of the month is the first day": {
+			durationString: "0d@1mon",
+			expected:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"fifth friday falls back to the last one": {
+			durationString: "1m@5fri",
+			expected:       time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC),
+		},
+		"last friday": {
+			durationString: "2m@lastfriday",
+			expected:       time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"anchor after subtraction": {
+			durationString: "-1m@eom",
+			expected:       time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d, err := qduration.Parse(tc.durationString)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, d.AddTo(base))
+		})
+	}
+}
+
+func Test_InvalidAnchorsResultInAnError(t *testing.T) {
+	for _, d := range []string{"1m@", "1m@6mon", "1m@2xyz", "1m@lastday"} {
+		_, err := qduration.Parse(d)
+		assert.Error(t, err, d)
+	}
+}
+
+func Test_ReadHolidays(t *testing.T) {
+	days, err := qduration.ReadHolidays(strings.NewReader("# Christmas\n2023-12-25\n\n2023-12-26\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC)}, days)
+
+	_, err = qduration.ReadHolidays(strings.NewReader("2023-12-25\nchristmas\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
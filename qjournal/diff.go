@@ -0,0 +1,146 @@
+package qjournal
+
+import (
+	"errors"
+	"slices"
+)
+
+// maxDiffCells limits the size of the LCS table. If the changed region
+// is larger than that, the whole region is recorded as a single hunk.
+var maxDiffCells = 4_000_000
+
+var ErrConflict = errors.New("the file has been changed in the meantime")
+
+// Hunk describes a contiguous block of lines that was replaced.
+// Before and After are the (0-based) positions of the block in the
+// old and the new version of the file.
+type Hunk struct {
+	Before  int      `json:"before"`
+	After   int      `json:"after"`
+	Removed []string `json:"removed,omitempty"`
+	Added   []string `json:"added,omitempty"`
+}
+
+func (h Hunk) inverse() Hunk {
+	return Hunk{
+		Before:  h.After,
+		After:   h.Before,
+		Removed: h.Added,
+		Added:   h.Removed,
+	}
+}
+
+// Diff computes the hunks that turn before into after.
+func Diff(before, after []string) []Hunk {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix && before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	b := before[prefix : len(before)-suffix]
+	a := after[prefix : len(after)-suffix]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	if len(a)*len(b) > maxDiffCells {
+		return []Hunk{{Before: prefix, After: prefix, Removed: slices.Clone(b), Added: slices.Clone(a)}}
+	}
+
+	lcs := make([][]int, len(b)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(a)+1)
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		for j := len(a) - 1; j >= 0; j-- {
+			if b[i] == a[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	hunks := make([]Hunk, 0)
+	var current *Hunk
+	flush := func() {
+		if current != nil {
+			hunks = append(hunks, *current)
+			current = nil
+		}
+	}
+	start := func(i, j int) {
+		if current == nil {
+			current = &Hunk{Before: prefix + i, After: prefix + j}
+		}
+	}
+	i, j := 0, 0
+	for i < len(b) || j < len(a) {
+		switch {
+		case i < len(b) && j < len(a) && b[i] == a[j]:
+			flush()
+			i++
+			j++
+		case j < len(a) && (i == len(b) || lcs[i][j+1] >= lcs[i+1][j]):
+			start(i, j)
+			current.Added = append(current.Added, a[j])
+			j++
+		default:
+			start(i, j)
+			current.Removed = append(current.Removed, b[i])
+			i++
+		}
+	}
+	flush()
+	return hunks
+}
+
+// Patch applies the hunks to lines. If a hunk can not be found at its
+// recorded position, it is searched for in the whole file, so that
+// unrelated changes to the file do not prevent the patch from being applied.
+func Patch(lines []string, hunks []Hunk) ([]string, error) {
+	result := slices.Clone(lines)
+	offset := 0
+	for _, h := range hunks {
+		pos := h.Before + offset
+		if !matchesAt(result, h.Removed, pos) {
+			pos = find(result, h.Removed, pos)
+			if pos == -1 {
+				return nil, ErrConflict
+			}
+		}
+		pos = min(pos, len(result))
+		result = slices.Replace(result, pos, pos+len(h.Removed), h.Added...)
+		offset += len(h.Added) - len(h.Removed)
+	}
+	return result, nil
+}
+
+func matchesAt(lines []string, block []string, pos int) bool {
+	if pos < 0 || pos+len(block) > len(lines) {
+		return len(block) == 0 && pos >= 0
+	}
+	return slices.Equal(lines[pos:pos+len(block)], block)
+}
+
+func find(lines []string, block []string, near int) int {
+	if len(block) == 0 {
+		return -1
+	}
+	best := -1
+	for i := 0; i+len(block) <= len(lines); i++ {
+		if slices.Equal(lines[i:i+len(block)], block) && (best == -1 || abs(i-near) < abs(best-near)) {
+			best = i
+		}
+	}
+	return best
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+package qjournal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiffAndPatch(t *testing.T) {
+	testCases := map[string]struct {
+		before []string
+		after  []string
+	}{
+		"no changes": {
+			before: []string{"a", "b"},
+			after:  []string{"a", "b"},
+		},
+		"append": {
+			before: []string{"a", "b"},
+			after:  []string{"a", "b", "c"},
+		},
+		"remove in the middle": {
+			before: []string{"a", "b", "c"},
+			after:  []string{"a", "c"},
+		},
+		"modify multiple lines": {
+			before: []string{"a", "b", "c", "d", "e"},
+			after:  []string{"a", "B", "c", "D", "e", "f"},
+		},
+		"from empty file": {
+			before: nil,
+			after:  []string{"a", "b"},
+		},
+		"to empty file": {
+			before: []string{"a", "b"},
+			after:  nil,
+		},
+		"reorder": {
+			before: []string{"a", "b", "c"},
+			after:  []string{"c", "a", "b"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hunks := Diff(tc.before, tc.after)
+			patched, err := Patch(tc.before, hunks)
+			assert.Nil(t, err)
+			assert.Equal(t, len(tc.after), len(patched))
+			assert.Equal(t, tc.after, append([]string(nil), patched...))
+
+			reverted, err := Patch(tc.after, Entry{Changes: []FileChange{{Hunks: hunks}}}.Inverse().Changes[0].Hunks)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.before, append([]string(nil), reverted...))
+		})
+	}
+}
+
+func Test_PatchFindsMovedLines(t *testing.T) {
+	hunks := Diff([]string{"a", "b"}, []string{"a", "B"})
+
+	patched, err := Patch([]string{"x", "y", "a", "b"}, hunks)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"x", "y", "a", "B"}, patched)
+}
+
+func Test_PatchDetectsConflicts(t *testing.T) {
+	hunks := Diff([]string{"a", "b"}, []string{"a", "B"})
+
+	_, err := Patch([]string{"a", "c"}, hunks)
+
+	assert.ErrorIs(t, err, ErrConflict)
+}
@@ -0,0 +1,228 @@
+package qjournal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var ErrNothingToUndo = errors.New("there is nothing to undo")
+var ErrNothingToRedo = errors.New("there is nothing to redo")
+
+// FileChange records the changes that were made to a single file.
+type FileChange struct {
+	File  string `json:"file"`
+	Hunks []Hunk `json:"hunks"`
+}
+
+func (f FileChange) inverse() FileChange {
+	hunks := make([]Hunk, 0, len(f.Hunks))
+	for _, h := range f.Hunks {
+		hunks = append(hunks, h.inverse())
+	}
+	return FileChange{
+		File:  f.File,
+		Hunks: hunks,
+	}
+}
+
+// Entry groups all changes of a single quest invocation.
+type Entry struct {
+	Invocation string       `json:"invocation"`
+	Time       time.Time    `json:"time"`
+	Command    string       `json:"command"`
+	Changes    []FileChange `json:"changes"`
+}
+
+// Inverse returns an entry that reverts the changes of e.
+func (e Entry) Inverse() Entry {
+	changes := make([]FileChange, 0, len(e.Changes))
+	for i := len(e.Changes) - 1; i >= 0; i-- {
+		changes = append(changes, e.Changes[i].inverse())
+	}
+	e.Changes = changes
+	return e
+}
+
+// Files returns the files that are touched by the entry.
+func (e Entry) Files() []string {
+	files := make([]string, 0, len(e.Changes))
+	for _, c := range e.Changes {
+		if !slices.Contains(files, c.File) {
+			files = append(files, c.File)
+		}
+	}
+	return files
+}
+
+type journalData struct {
+	// Applied is the number of entries that are currently applied.
+	// Entries after that can be redone.
+	Applied int     `json:"applied"`
+	Entries []Entry `json:"entries"`
+}
+
+// Journal records changes to the todo.txt files, so that they can be reverted later.
+// All changes that are recorded by the same Journal instance belong to the same entry,
+// until NewInvocation is called.
+type Journal struct {
+	file       string
+	invocation string
+	Keep       int
+	NowFunc    func() time.Time
+}
+
+func NewJournal(file string, keep int) *Journal {
+	return &Journal{
+		file:       file,
+		invocation: newInvocation(),
+		Keep:       keep,
+		NowFunc:    time.Now,
+	}
+}
+
+var invocations atomic.Int64
+
+func newInvocation() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(invocations.Add(1), 36)
+}
+
+// NewInvocation makes the following changes a new entry, e.g. for every action of an interactive session.
+func (j *Journal) NewInvocation() {
+	j.invocation = newInvocation()
+}
+
+// Location returns the journal path that belongs to the given todo file.
+func Location(todoFile string) string {
+	extension := path.Ext(todoFile)
+	fileName := path.Base(todoFile[:len(todoFile)-len(extension)])
+	return path.Join(path.Dir(todoFile), fmt.Sprintf(".%s.quest-history.json", fileName))
+}
+
+// Enabled reports whether changes are recorded at all
+func (j *Journal) Enabled() bool {
+	return j.Keep > 0
+}
+
+// Record adds the change from before to after to the journal.
+// Redoable entries are discarded.
+func (j *Journal) Record(command string, file string, before, after []string) error {
+	if !j.Enabled() {
+		return nil
+	}
+	hunks := Diff(before, after)
+	if len(hunks) == 0 {
+		return nil
+	}
+	data, err := j.load()
+	if err != nil {
+		return err
+	}
+	data.Entries = data.Entries[:data.Applied]
+	change := FileChange{File: file, Hunks: hunks}
+	if last := len(data.Entries) - 1; last >= 0 && data.Entries[last].Invocation == j.invocation {
+		data.Entries[last].Changes = append(data.Entries[last].Changes, change)
+	} else {
+		data.Entries = append(data.Entries, Entry{
+			Invocation: j.invocation,
+			Time:       j.NowFunc(),
+			Command:    command,
+			Changes:    []FileChange{change},
+		})
+	}
+	if len(data.Entries) > j.Keep {
+		data.Entries = data.Entries[len(data.Entries)-j.Keep:]
+	}
+	data.Applied = len(data.Entries)
+	return j.store(data)
+}
+
+// History returns all recorded entries (oldest first) and the number of entries
+// that are currently applied.
+func (j *Journal) History() ([]Entry, int, error) {
+	data, err := j.load()
+	if err != nil {
+		return nil, 0, err
+	}
+	return data.Entries, data.Applied, nil
+}
+
+// Undo reverts the most recent applied entry. The actual file changes are
+// performed by apply, which receives the inverted entry.
+func (j *Journal) Undo(apply func(Entry) error) (Entry, error) {
+	data, err := j.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	if data.Applied == 0 {
+		return Entry{}, ErrNothingToUndo
+	}
+	entry := data.Entries[data.Applied-1]
+	if err := apply(entry.Inverse()); err != nil {
+		return Entry{}, err
+	}
+	data.Applied--
+	return entry, j.store(data)
+}
+
+// Redo applies the oldest entry that was undone.
+func (j *Journal) Redo(apply func(Entry) error) (Entry, error) {
+	data, err := j.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	if data.Applied == len(data.Entries) {
+		return Entry{}, ErrNothingToRedo
+	}
+	entry := data.Entries[data.Applied]
+	if err := apply(entry); err != nil {
+		return Entry{}, err
+	}
+	data.Applied++
+	return entry, j.store(data)
+}
+
+func (j *Journal) load() (journalData, error) {
+	raw, err := os.ReadFile(j.file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return journalData{}, nil
+	}
+	if err != nil {
+		return journalData{}, fmt.Errorf("could not read journal %s: %w", j.file, err)
+	}
+	data := journalData{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return journalData{}, fmt.Errorf("could not parse journal %s: %w", j.file, err)
+	}
+	data.Applied = min(max(data.Applied, 0), len(data.Entries))
+	return data, nil
+}
+
+func (j *Journal) store(data journalData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(path.Dir(j.file), ".quest.part.*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("could not write journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not close tmp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), j.file); err != nil {
+		return fmt.Errorf("could not move tmp file to final location: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package qjournal_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/Fabian-G/quest/qjournal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestJournal(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "quest-journal-test-*")
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return path.Join(dir, ".todo.quest-history.json")
+}
+
+func Test_ChangesOfOneInvocationAreGrouped(t *testing.T) {
+	file := newTestJournal(t)
+	journal := qjournal.NewJournal(file, 5)
+
+	assert.Nil(t, journal.Record("quest archive", "todo.txt", []string{"x a", "b"}, []string{"b"}))
+	assert.Nil(t, journal.Record("quest archive", "done.txt", nil, []string{"x a"}))
+
+	entries, applied, err := journal.History()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, applied)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, []string{"todo.txt", "done.txt"}, entries[0].Files())
+}
+
+func Test_NewInvocationStartsANewEntry(t *testing.T) {
+	file := newTestJournal(t)
+	journal := qjournal.NewJournal(file, 5)
+
+	assert.Nil(t, journal.Record("first", "todo.txt", nil, []string{"a"}))
+	journal.NewInvocation()
+	assert.Nil(t, journal.Record("second", "todo.txt", []string{"a"}, []string{"a", "b"}))
+
+	entries, applied, err := journal.History()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, applied)
+	assert.Equal(t, "first", entries[0].Command)
+	assert.Equal(t, "second", entries[1].Command)
+}
+
+func Test_UndoAndRedoMoveThroughTheHistory(t *testing.T) {
+	file := newTestJournal(t)
+	assert.Nil(t, qjournal.NewJournal(file, 5).Record("first", "todo.txt", nil, []string{"a"}))
+	assert.Nil(t, qjournal.NewJournal(file, 5).Record("second", "todo.txt", []string{"a"}, []string{"a", "b"}))
+	journal := qjournal.NewJournal(file, 5)
+	noop := func(qjournal.Entry) error { return nil }
+
+	entry, err := journal.Undo(noop)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", entry.Command)
+	entry, err = journal.Undo(noop)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", entry.Command)
+	_, err = journal.Undo(noop)
+	assert.ErrorIs(t, err, qjournal.ErrNothingToUndo)
+
+	entry, err = journal.Redo(noop)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", entry.Command)
+
+	assert.Nil(t, qjournal.NewJournal(file, 5).Record("third", "todo.txt", []string{"a"}, nil))
+	entries, applied, err := journal.History()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, applied)
+	assert.Equal(t, "third", entries[1].Command)
+	_, err = journal.Redo(noop)
+	assert.ErrorIs(t, err, qjournal.ErrNothingToRedo)
+}
+
+func Test_JournalKeepsOnlyTheConfiguredNumberOfEntries(t *testing.T) {
+	file := newTestJournal(t)
+	for _, c := range []string{"a", "b", "c"} {
+		assert.Nil(t, qjournal.NewJournal(file, 2).Record(c, "todo.txt", nil, []string{c}))
+	}
+
+	entries, _, err := qjournal.NewJournal(file, 2).History()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "b", entries[0].Command)
+}
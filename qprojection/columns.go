@@ -34,6 +34,11 @@ type columnDef struct {
 
 var columns = []columnDef{
 	lineColumn,
+	idColumn,
+	blockersColumn,
+	progressColumn,
+	workspaceColumn,
+	fileColumn,
 	tagColumn,
 	doneColumn,
 	priorityColumn,
@@ -71,6 +76,15 @@ var tagColumn = columnDef{
 					}
 					tagValues = append(tagValues, humanTime(d))
 				}
+			} else if p.TagTypes[tagKey] == qselect.QDateTime && slices.Contains(p.HumanizedTags, tagKey) {
+				for _, v := range i.Tags()[tagKey] {
+					d, err := time.Parse(qselect.DateTimeLayout, v)
+					if err != nil {
+						tagValues = append(tagValues, v)
+						continue
+					}
+					tagValues = append(tagValues, humanDateTime(d))
+				}
 			} else {
 				tagValues = i.Tags()[tagKey]
 			}
@@ -174,6 +188,73 @@ var lineColumn = columnDef{
 	},
 }
 
+var idColumn = columnDef{
+	matcher: staticMatch("id"),
+	name:    staticName("Id"),
+	extractor: staticColumn(func(p Projector, list *todotxt.List, item *todotxt.Item) (string, lipgloss.Color) {
+		if p.IdTag == "" {
+			return "", p.defaultColor
+		}
+		return strings.Join(item.Tags()[p.IdTag], ","), p.defaultColor
+	}),
+}
+
+var blockersColumn = columnDef{
+	matcher: staticMatch("blockers"),
+	name:    staticName("Blocked By"),
+	extractor: staticColumn(func(p Projector, list *todotxt.List, item *todotxt.Item) (string, lipgloss.Color) {
+		if p.IdTag == "" {
+			return "", p.defaultColor
+		}
+		blockers := qselect.BlockersOf(list, item)
+		if len(blockers) == 0 {
+			return "", p.defaultColor
+		}
+		ids := make([]string, 0, len(blockers))
+		for _, b := range blockers {
+			ids = append(ids, strings.Join(b.Tags()[p.IdTag], ","))
+		}
+		return strings.Join(ids, ","), lipgloss.Color("1")
+	}),
+}
+
+var progressColumn = columnDef{
+	matcher: staticMatch("progress"),
+	name:    staticName("Progress"),
+	extractor: staticColumn(func(p Projector, list *todotxt.List, item *todotxt.Item) (string, lipgloss.Color) {
+		if len(qselect.ChildrenOf(list, item)) == 0 {
+			return "", p.defaultColor
+		}
+		progress := qselect.ProgressOf(list, item)
+		if progress == 100 {
+			return fmt.Sprintf("%d%%", progress), lipgloss.Color("2")
+		}
+		return fmt.Sprintf("%d%%", progress), p.defaultColor
+	}),
+}
+
+var workspaceColumn = columnDef{
+	matcher: staticMatch("workspace"),
+	name:    staticName("Workspace"),
+	extractor: staticColumn(func(p Projector, list *todotxt.List, item *todotxt.Item) (string, lipgloss.Color) {
+		if source := list.SourceOf(item); source != nil {
+			return source.Name, p.defaultColor
+		}
+		return "", p.defaultColor
+	}),
+}
+
+var fileColumn = columnDef{
+	matcher: staticMatch("file"),
+	name:    staticName("File"),
+	extractor: staticColumn(func(p Projector, list *todotxt.List, item *todotxt.Item) (string, lipgloss.Color) {
+		if source := list.SourceOf(item); source != nil {
+			return source.File(), p.defaultColor
+		}
+		return "", p.defaultColor
+	}),
+}
+
 var descriptionColumn = columnDef{
 	matcher: regexMatch("description(\\([0-9]+\\))?"),
 	name:    staticName("Description"),
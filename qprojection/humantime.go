@@ -27,6 +27,17 @@ func humanTime(then time.Time) string {
 	return relTime(then, today, "ago", "in")
 }
 
+// humanDateTime formats a datetime into a relative string.
+// Unlike humanTime it takes the time of day into account.
+//
+// humanDateTime(someT) -> "in 3 hours"
+func humanDateTime(then time.Time) string {
+	then = time.Date(then.Year(), then.Month(), then.Day(), then.Hour(), then.Minute(), 0, 0, time.UTC)
+	now := time.Now()
+	now = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
+	return customRelTime(then, now, "ago", "in", dateTimeMagnitudes)
+}
+
 // A relTimeMagnitude struct contains a relative time point at which
 // the relative format of time will switch to a new format string.  A
 // slice of these in ascending order by their "D" field is passed to
@@ -60,6 +71,14 @@ var defaultMagnitudes = []relTimeMagnitude{
 	{math.MaxInt64, "a long while %s", "%s a long while", 1},
 }
 
+var dateTimeMagnitudes = append([]relTimeMagnitude{
+	{time.Minute, "now", "now", 1},
+	{2 * time.Minute, "1 minute %s", "%s 1 minute", 1},
+	{time.Hour, "%d minutes %s", "%s %d minutes", time.Minute},
+	{2 * time.Hour, "1 hour %s", "%s 1 hour", 1},
+	{day, "%d hours %s", "%s %d hours", time.Hour},
+}, defaultMagnitudes[1:]...)
+
 // relTime formats a time into a relative string.
 //
 // It takes two times and two labels.  In addition to the generic time
@@ -19,6 +19,7 @@ type Projector struct {
 	TagTypes      map[string]qselect.DType
 	TagColors     map[string]ColorFunc
 	LineColors    ColorFunc
+	IdTag         string
 	colorOverride *lipgloss.Color
 	defaultColor  lipgloss.Color
 }
@@ -0,0 +1,145 @@
+package qselect
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+var ErrDependenciesWithoutIds = errors.New("dependencies refer to stable task ids. Configure ids.tag to use them")
+
+var dependencyTag string
+
+// RegisterDependencyTag enables the blocked, blockers and dependents functions for
+// the given tag. The tag refers to the ids of other tasks (see RegisterIdTag). An empty tag disables them again.
+func RegisterDependencyTag(tag string) error {
+	if tag != "" && idTag == "" {
+		return ErrDependenciesWithoutIds
+	}
+	dependencyTag = tag
+	if tag == "" {
+		delete(functions, "blocked")
+		delete(functions, "blockers")
+		delete(functions, "dependents")
+		return nil
+	}
+	functions["blocked"] = queryFunc{
+		fn:               blocked,
+		argTypes:         []DType{QItem},
+		resultType:       QBool,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	}
+	functions["blockers"] = queryFunc{
+		fn:               blockers,
+		argTypes:         []DType{QItem},
+		resultType:       QItemSlice,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	}
+	functions["dependents"] = queryFunc{
+		fn:               dependents,
+		argTypes:         []DType{QItem},
+		resultType:       QItemSlice,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	}
+	return nil
+}
+
+func blocked(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	return len(BlockersOf(list, item)) > 0
+}
+
+func blockers(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	return toAnySlice(BlockersOf(list, item))
+}
+
+func dependents(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	return toAnySlice(DependentsOf(list, item))
+}
+
+// DependenciesOf returns the ids item depends on. Multiple ids may be given as separate tags or separated by commas.
+func DependenciesOf(item *todotxt.Item, tag string) []string {
+	ids := make([]string, 0)
+	for _, v := range item.TagValues(tag) {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// BlockersOf returns the tasks item depends on that are not done yet.
+// Ids of tasks that are not part of the list (e.g. because they were archived) do not block.
+func BlockersOf(list *todotxt.List, item *todotxt.Item) []*todotxt.Item {
+	if dependencyTag == "" {
+		return nil
+	}
+	index := dependencyIndexOf(list)
+	blockers := make([]*todotxt.Item, 0)
+	for _, id := range DependenciesOf(item, dependencyTag) {
+		if blocker, ok := index.byId[id]; ok && !blocker.Done() {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers
+}
+
+// DependentsOf returns all tasks that depend on item.
+func DependentsOf(list *todotxt.List, item *todotxt.Item) []*todotxt.Item {
+	if dependencyTag == "" || idOf(item) == "" {
+		return nil
+	}
+	return dependencyIndexOf(list).dependents[idOf(item)]
+}
+
+type dependencyIndex struct {
+	byId       map[string]*todotxt.Item
+	dependents map[string][]*todotxt.Item
+}
+
+// depIndexCache remembers the index of the last list, so that blocked(it) is not O(n) for every item.
+var depIndexCache struct {
+	mu      sync.Mutex
+	list    *todotxt.List
+	version uint64
+	index   dependencyIndex
+}
+
+func dependencyIndexOf(list *todotxt.List) dependencyIndex {
+	depIndexCache.mu.Lock()
+	defer depIndexCache.mu.Unlock()
+	if depIndexCache.list == list && depIndexCache.version == list.Version() {
+		return depIndexCache.index
+	}
+	index := dependencyIndex{
+		byId:       make(map[string]*todotxt.Item),
+		dependents: make(map[string][]*todotxt.Item),
+	}
+	for _, t := range list.Tasks() {
+		if id := idOf(t); id != "" {
+			if _, ok := index.byId[id]; !ok {
+				index.byId[id] = t
+			}
+		}
+		for _, dep := range DependenciesOf(t, dependencyTag) {
+			index.dependents[dep] = append(index.dependents[dep], t)
+		}
+	}
+	depIndexCache.list, depIndexCache.version, depIndexCache.index = list, list.Version(), index
+	return index
+}
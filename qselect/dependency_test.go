@@ -0,0 +1,78 @@
+package qselect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DependencyFunctions(t *testing.T) {
+	RegisterIdTag("id")
+	assert.Nil(t, RegisterDependencyTag("after"))
+	defer RegisterIdTag("")
+	defer RegisterDependencyTag("")
+	list := listFromString(t, `
+	write tests id:a
+	x 2022-01-02 2022-01-01 write code id:b
+	release id:c after:a,b
+	announce id:d after:c after:e
+	`)
+	testCases := map[string]struct {
+		query           string
+		expectedMatches []string
+	}{
+		"blocked": {
+			query:           `blocked(it)`,
+			expectedMatches: []string{"release id:c after:a,b", "announce id:d after:c after:e"},
+		},
+		"done and unknown tasks do not block": {
+			query:           `exists b in blockers(it): done(b) || tag(b, "id") == "e"`,
+			expectedMatches: []string{},
+		},
+		"blockers": {
+			query:           `exists b in blockers(it): tag(b, "id") == "a"`,
+			expectedMatches: []string{"release id:c after:a,b"},
+		},
+		"not blocked": {
+			query:           `!blocked(it)`,
+			expectedMatches: []string{"write tests id:a", "x 2022-01-02 2022-01-01 write code id:b"},
+		},
+		"dependents": {
+			query:           `exists d in dependents(it): tag(d, "id") == "c"`,
+			expectedMatches: []string{"write tests id:a", "x 2022-01-02 2022-01-01 write code id:b"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			query, err := CompileQQL(tc.query)
+			assert.Nil(t, err)
+			matches := query.Filter(list)
+			lines := make([]string, 0, len(matches))
+			for _, m := range matches {
+				lines = append(lines, m.String())
+			}
+			assert.ElementsMatch(t, tc.expectedMatches, lines)
+		})
+	}
+}
+
+func Test_DependencyTagRequiresIds(t *testing.T) {
+	assert.ErrorIs(t, RegisterDependencyTag("after"), ErrDependenciesWithoutIds)
+}
+
+func Test_BlockersAreUpdatedWhenTheListChanges(t *testing.T) {
+	RegisterIdTag("id")
+	assert.Nil(t, RegisterDependencyTag("after"))
+	defer RegisterIdTag("")
+	defer RegisterDependencyTag("")
+	list := listFromString(t, `
+	write tests id:a
+	release id:c after:a
+	`)
+	assert.Len(t, BlockersOf(list, list.GetLine(2)), 1)
+
+	assert.Nil(t, list.GetLine(1).Complete())
+
+	assert.Empty(t, BlockersOf(list, list.GetLine(2)))
+}
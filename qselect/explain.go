@@ -0,0 +1,163 @@
+package qselect
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/Fabian-G/quest/qduration"
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+// Explanation describes the value of a (sub) expression of a query for one specific item.
+// Children contains the explanations of the sub expressions that were relevant for the value.
+type Explanation struct {
+	Expression string
+	Value      any
+	Note       string
+	Children   []Explanation
+}
+
+func (e Explanation) String() string {
+	builder := strings.Builder{}
+	e.write(&builder, "", "")
+	return builder.String()
+}
+
+func (e Explanation) write(builder *strings.Builder, firstPrefix, prefix string) {
+	fmt.Fprintf(builder, "%s%s = %s", firstPrefix, e.Expression, formatValue(e.Value))
+	if e.Note != "" {
+		fmt.Fprintf(builder, " (%s)", e.Note)
+	}
+	builder.WriteString("\n")
+	for i, c := range e.Children {
+		if i == len(e.Children)-1 {
+			c.write(builder, prefix+"└─ ", prefix+"   ")
+		} else {
+			c.write(builder, prefix+"├─ ", prefix+"│  ")
+		}
+	}
+}
+
+// ExplainQQL evaluates query against item and explains how the result came about.
+func ExplainQQL(query string, list *todotxt.List, item *todotxt.Item) (Explanation, error) {
+	root, err := parseQQLTree(query, maps.Clone(defaultFreeVars), QBool)
+	if err != nil {
+		return Explanation{}, err
+	}
+	return explain(root, buildFreeVars(list, item)), nil
+}
+
+func explain(n node, alpha varMap) Explanation {
+	switch n := n.(type) {
+	case *and:
+		left := explain(n.leftChild, alpha)
+		if !left.Value.(bool) {
+			return Explanation{Expression: n.String(), Value: false, Children: []Explanation{left}}
+		}
+		right := explain(n.rightChild, alpha)
+		return Explanation{Expression: n.String(), Value: right.Value, Children: []Explanation{left, right}}
+	case *or:
+		left := explain(n.leftChild, alpha)
+		if left.Value.(bool) {
+			return Explanation{Expression: n.String(), Value: true, Children: []Explanation{left}}
+		}
+		right := explain(n.rightChild, alpha)
+		return Explanation{Expression: n.String(), Value: right.Value, Children: []Explanation{left, right}}
+	case *impl:
+		left := explain(n.leftChild, alpha)
+		if !left.Value.(bool) {
+			return Explanation{Expression: n.String(), Value: true, Children: []Explanation{left}}
+		}
+		right := explain(n.rightChild, alpha)
+		return Explanation{Expression: n.String(), Value: right.Value, Children: []Explanation{left, right}}
+	case *not:
+		child := explain(n.child, alpha)
+		return Explanation{Expression: n.String(), Value: !child.Value.(bool), Children: []Explanation{child}}
+	case *existQuant:
+		return explainQuantifier(n.String(), n.boundId, n.collection, n.child, true, alpha)
+	case *allQuant:
+		return explainQuantifier(n.String(), n.boundId, n.collection, n.child, false, alpha)
+	case *call:
+		if n.passThrough {
+			return explain(n.ifBound, alpha)
+		}
+	case *comparison:
+		return explainOperands(n, alpha, n.leftChild, n.rightChild)
+	case *membership:
+		return explainOperands(n, alpha, n.element, n.collection)
+	case *regexMatch:
+		return explainOperands(n, alpha, n.str, n.pattern)
+	}
+	return Explanation{Expression: n.String(), Value: n.eval(alpha)}
+}
+
+// explainOperands explains a binary operator by listing the values of its non constant operands
+func explainOperands(n node, alpha varMap, operands ...node) Explanation {
+	e := Explanation{Expression: n.String(), Value: n.eval(alpha)}
+	for _, o := range operands {
+		switch o.(type) {
+		case *stringConst, *intConst, *durationConst, *boolConst:
+			continue
+		}
+		e.Children = append(e.Children, explain(o, alpha))
+	}
+	return e
+}
+
+// explainQuantifier finds the binding that decides the quantifier. For exists this is the first element
+// that satisfies the child, for forall the first one that does not.
+func explainQuantifier(expression string, boundId string, collection node, child node, exists bool, alpha varMap) Explanation {
+	prevValue := alpha[boundId]
+	defer func() { alpha[boundId] = prevValue }()
+	elements := collection.eval(alpha).([]any)
+	for _, element := range elements {
+		alpha[boundId] = element
+		if child.eval(alpha).(bool) == exists {
+			return Explanation{
+				Expression: expression,
+				Value:      exists,
+				Note:       fmt.Sprintf("decided by %s = %s", boundId, formatValue(element)),
+				Children:   []Explanation{explain(child, alpha)},
+			}
+		}
+	}
+	note := fmt.Sprintf("no element of %d satisfies the expression", len(elements))
+	if !exists {
+		note = fmt.Sprintf("all %d elements satisfy the expression", len(elements))
+	}
+	return Explanation{Expression: expression, Value: !exists, Note: note}
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case time.Time:
+		if v.Hour() != 0 || v.Minute() != 0 {
+			return v.Format(DateTimeLayout)
+		}
+		return v.Format(time.DateOnly)
+	case qduration.Duration:
+		if v.Minutes()%(24*60) != 0 {
+			return fmt.Sprintf("%dmin", v.Minutes())
+		}
+		return fmt.Sprintf("%dd", v.Days())
+	case todotxt.Priority:
+		if v == todotxt.PrioNone {
+			return "prioNone"
+		}
+		return v.String()
+	case *todotxt.Item:
+		return fmt.Sprintf("%q", v.String())
+	case []any:
+		elements := make([]string, 0, len(v))
+		for _, e := range v {
+			elements = append(elements, formatValue(e))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
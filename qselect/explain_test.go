@@ -0,0 +1,39 @@
+package qselect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExplainShowsTheDecidingQuantifierBinding(t *testing.T) {
+	list := listFromString(t, "a task +foo +bar @home")
+
+	explanation, err := ExplainQQL(`exists p in projects: p == "+bar"`, list, list.GetLine(1))
+	assert.Nil(t, err)
+	assert.Equal(t, true, explanation.Value)
+	assert.Equal(t, `decided by p = "+bar"`, explanation.Note)
+
+	explanation, err = ExplainQQL(`forall c in contexts: c == "@work"`, list, list.GetLine(1))
+	assert.Nil(t, err)
+	assert.Equal(t, false, explanation.Value)
+	assert.Equal(t, `decided by c = "@home"`, explanation.Note)
+}
+
+func Test_ExplainShortCircuits(t *testing.T) {
+	list := listFromString(t, "(A) a task +foo")
+
+	explanation, err := ExplainQQL(`priority == prioB && +foo`, list, list.GetLine(1))
+	assert.Nil(t, err)
+	assert.Equal(t, false, explanation.Value)
+	assert.Len(t, explanation.Children, 1)
+	assert.Equal(t, false, explanation.Children[0].Value)
+	assert.Contains(t, explanation.String(), "priority(it) = (A)")
+}
+
+func Test_ExplainFailsForInvalidQueries(t *testing.T) {
+	list := listFromString(t, "a task")
+
+	_, err := ExplainQQL(`priority ==`, list, list.GetLine(1))
+	assert.Error(t, err)
+}
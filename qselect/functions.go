@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"maps"
 	"os/exec"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/Fabian-G/quest/qduration"
 	"github.com/Fabian-G/quest/todotxt"
 )
 
@@ -154,6 +157,22 @@ var functions = map[string]queryFunc{
 		injectIt:         false,
 		wantsContext:     false,
 	},
+	"datetime": {
+		fn:               toDateTime,
+		resultType:       QDateTime,
+		argTypes:         []DType{QString, QDateTime},
+		trailingOptional: true,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"day": {
+		fn:               day,
+		resultType:       QDate,
+		argTypes:         []DType{QDateTime},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
 	"tag": {
 		fn:               tag,
 		resultType:       QString,
@@ -194,6 +213,136 @@ var functions = map[string]queryFunc{
 		injectIt:         false,
 		wantsContext:     false,
 	},
+	"lower": {
+		fn:               lower,
+		resultType:       QString,
+		argTypes:         []DType{QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"upper": {
+		fn:               upper,
+		resultType:       QString,
+		argTypes:         []DType{QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"trim": {
+		fn:               trim,
+		resultType:       QString,
+		argTypes:         []DType{QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"startsWith": {
+		fn:               startsWith,
+		resultType:       QBool,
+		argTypes:         []DType{QString, QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"endsWith": {
+		fn:               endsWith,
+		resultType:       QBool,
+		argTypes:         []DType{QString, QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"split": {
+		fn:               split,
+		resultType:       QStringSlice,
+		argTypes:         []DType{QString, QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"len": {
+		fn:               length,
+		resultType:       QInt,
+		argTypes:         []DType{QString},
+		trailingOptional: false,
+		injectIt:         false,
+		wantsContext:     false,
+	},
+	"workspace": {
+		fn:               workspace,
+		resultType:       QString,
+		argTypes:         []DType{QItem},
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	},
+	"archived": {
+		fn:               archived,
+		resultType:       QBool,
+		argTypes:         []DType{QItem},
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	},
+	"file": {
+		fn:               file,
+		resultType:       QString,
+		argTypes:         []DType{QItem},
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	},
+}
+
+type aggregateFunc struct {
+	fn         func(values []any, valueType DType) any
+	valueTypes []DType // nil means every type is allowed
+	resultType DType   // QError means the result has the type of the values
+	predicate  bool    // if true, the lambda filters the collection instead of mapping it
+}
+
+var aggregates = map[string]aggregateFunc{
+	"count": {
+		fn:         count,
+		valueTypes: nil,
+		resultType: QInt,
+		predicate:  true,
+	},
+	"sum": {
+		fn:         sum,
+		valueTypes: []DType{QInt},
+		resultType: QInt,
+		predicate:  false,
+	},
+	"avg": {
+		fn:         avg,
+		valueTypes: []DType{QInt},
+		resultType: QInt,
+		predicate:  false,
+	},
+	"min": {
+		fn:         minimum,
+		valueTypes: []DType{QInt, QDate, QDateTime, QString, QPriority},
+		resultType: QError,
+		predicate:  false,
+	},
+	"max": {
+		fn:         maximum,
+		valueTypes: []DType{QInt, QDate, QDateTime, QString, QPriority},
+		resultType: QError,
+		predicate:  false,
+	},
+}
+
+func (a aggregateFunc) validate(valueType DType) (DType, error) {
+	if a.valueTypes != nil && !slices.Contains(a.valueTypes, valueType) {
+		return QError, fmt.Errorf("expecting values of type %v, but got %s", a.valueTypes, valueType)
+	}
+	if a.resultType == QError {
+		return valueType, nil
+	}
+	return a.resultType, nil
 }
 
 func RegisterMacro(name, qql string, inTypes []DType, outType DType, injectIt bool) error {
@@ -228,12 +377,100 @@ func RegisterMacro(name, qql string, inTypes []DType, outType DType, injectIt bo
 	return nil
 }
 
+func count(values []any, _ DType) any {
+	return len(values)
+}
+
+func sum(values []any, _ DType) any {
+	result := 0
+	for _, v := range values {
+		result += v.(int)
+	}
+	return result
+}
+
+func avg(values []any, t DType) any {
+	if len(values) == 0 {
+		return 0
+	}
+	return sum(values, t).(int) / len(values)
+}
+
+func minimum(values []any, t DType) any {
+	return extremum(values, t, itemLt)
+}
+
+func maximum(values []any, t DType) any {
+	return extremum(values, t, itemGt)
+}
+
+// extremum returns the value that is op (< or >) compared to all others.
+// For empty collections the zero value of the type is returned.
+func extremum(values []any, t DType, op itemType) any {
+	if len(values) == 0 {
+		return zeroValue(t)
+	}
+	result := values[0]
+	for _, v := range values[1:] {
+		if compare(op, t, t, v, result) {
+			result = v
+		}
+	}
+	return result
+}
+
+func zeroValue(t DType) any {
+	switch t {
+	case QBool:
+		return false
+	case QInt:
+		return 0
+	case QDuration:
+		return qduration.Duration{}
+	case QStringSlice, QIntSlice, QDateSlice, QItemSlice:
+		return []any{}
+	case QDate, QDateTime:
+		return time.Time{}
+	case QString:
+		return ""
+	case QPriority:
+		return todotxt.PrioNone
+	default:
+		panic(fmt.Errorf("type %s has no zero value", t))
+	}
+}
+
 func line(args []any) any {
 	list := args[0].(map[string]any)["_list"].(*todotxt.List)
 	item := args[1].(*todotxt.Item)
 	return list.LineOf(item)
 }
 
+func workspace(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	if source := list.SourceOf(item); source != nil {
+		return source.Name
+	}
+	return ""
+}
+
+func archived(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	source := list.SourceOf(item)
+	return source != nil && source.Archive
+}
+
+func file(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	if source := list.SourceOf(item); source != nil {
+		return source.File()
+	}
+	return ""
+}
+
 func done(args []any) any {
 	item := args[0].(*todotxt.Item)
 	return item.Done()
@@ -315,6 +552,38 @@ func substring(args []any) any {
 	return strings.Contains(s1, s2)
 }
 
+func lower(args []any) any {
+	return strings.ToLower(args[0].(string))
+}
+
+func upper(args []any) any {
+	return strings.ToUpper(args[0].(string))
+}
+
+func trim(args []any) any {
+	return strings.TrimSpace(args[0].(string))
+}
+
+func startsWith(args []any) any {
+	return strings.HasPrefix(args[0].(string), args[1].(string))
+}
+
+func endsWith(args []any) any {
+	return strings.HasSuffix(args[0].(string), args[1].(string))
+}
+
+func split(args []any) any {
+	s := args[0].(string)
+	if len(s) == 0 {
+		return []any{}
+	}
+	return toAnySlice(strings.Split(s, args[1].(string)))
+}
+
+func length(args []any) any {
+	return utf8.RuneCountInString(args[0].(string))
+}
+
 func ymd(args []any) any {
 	year := args[0].(int)
 	month := args[1].(int)
@@ -332,8 +601,7 @@ func tag(args []any) any {
 		defaultValue = args[2].(string)
 	}
 
-	tags := item.Tags()
-	tagValues := tags[key]
+	tagValues := item.TagValues(key)
 	if len(tagValues) == 0 {
 		return defaultValue
 	}
@@ -374,6 +642,27 @@ func toDate(args []any) any {
 	return result
 }
 
+// toDateTime parses a datetime (format YYYY-MM-ddTHH:mm). Dates without time of day are parsed as midnight.
+func toDateTime(args []any) any {
+	dateTimeString := args[0].(string)
+	defaultDateTime := time.Time{}
+	if len(args) == 2 {
+		defaultDateTime = args[1].(time.Time)
+	}
+	if result, err := time.Parse(DateTimeLayout, dateTimeString); err == nil {
+		return result
+	}
+	if result, err := time.Parse(time.DateOnly, dateTimeString); err == nil {
+		return result
+	}
+	return defaultDateTime
+}
+
+func day(args []any) any {
+	dateTime := args[0].(time.Time)
+	return time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func shell(args []any) any {
 	alpha := args[0].(map[string]any)
 	item := args[1].(*todotxt.Item)
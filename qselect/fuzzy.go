@@ -0,0 +1,144 @@
+package qselect
+
+import (
+	"slices"
+	"sync"
+	"unicode"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusConsecutive = 8
+	fuzzyPenaltyGapStart  = 3
+	fuzzyPenaltyGapExtend = 1
+)
+
+// FuzzyScore scores how well pattern matches text as a case-insensitive subsequence (similar to fzf).
+// Matches at word boundaries and consecutive matches score higher, gaps between matched characters lower the score.
+// ok is false if pattern is not a subsequence of text.
+func FuzzyScore(pattern string, text string) (score int, ok bool) {
+	p := []rune(toLowerString(pattern))
+	t := []rune(text)
+	if len(p) == 0 {
+		return 0, true
+	}
+
+	// Find the end of the first occurrence and then the shortest window ending there
+	pIdx, end := 0, -1
+	for i, r := range t {
+		if unicode.ToLower(r) == p[pIdx] {
+			pIdx++
+			if pIdx == len(p) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, false
+	}
+	pIdx, start := len(p)-1, end
+	for i := end; i >= 0; i-- {
+		if unicode.ToLower(t[i]) == p[pIdx] {
+			pIdx--
+			if pIdx < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	pIdx, prevMatch, inGap := 0, -1, false
+	for i := start; i <= end && pIdx < len(p); i++ {
+		if unicode.ToLower(t[i]) != p[pIdx] {
+			if inGap {
+				score -= fuzzyPenaltyGapExtend
+			} else {
+				score -= fuzzyPenaltyGapStart
+			}
+			inGap = true
+			continue
+		}
+		score += fuzzyScoreMatch
+		if isWordBoundary(t, i) {
+			score += fuzzyBonusBoundary
+		}
+		if prevMatch == i-1 {
+			score += fuzzyBonusConsecutive
+		}
+		prevMatch, inGap = i, false
+		pIdx++
+	}
+	return score, true
+}
+
+func isWordBoundary(text []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, cur := text[i-1], text[i]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+func toLowerString(s string) string {
+	r := []rune(s)
+	for i := range r {
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
+
+// FuzzyScoreOf returns the best FuzzyScore of pattern against the clean description, the projects and the contexts of i.
+func FuzzyScoreOf(pattern string, i *todotxt.Item) (score int, ok bool) {
+	candidates := []string{i.CleanDescription(i.Projects(), i.Contexts(), i.Tags().Keys())}
+	for _, p := range i.Projects() {
+		candidates = append(candidates, p.String())
+	}
+	for _, c := range i.Contexts() {
+		candidates = append(candidates, c.String())
+	}
+	for _, c := range candidates {
+		if s, match := FuzzyScore(pattern, c); match && (!ok || s > score) {
+			score, ok = s, true
+		}
+	}
+	return score, ok
+}
+
+func compileFuzzySearch(query string) Func {
+	return func(l *todotxt.List, i *todotxt.Item) bool {
+		_, ok := FuzzyScoreOf(query, i)
+		return ok
+	}
+}
+
+// compileWordOrFuzzySearch behaves like the word search, but falls back to a fuzzy search
+// if the query does not occur in any task of the list.
+func compileWordOrFuzzySearch(query string) Func {
+	word, fuzzy := compileStringSearch(query), compileFuzzySearch(query)
+	var mu sync.Mutex
+	var checkedList *todotxt.List
+	var checkedVersion uint64
+	anyWordMatch := false
+	return func(l *todotxt.List, i *todotxt.Item) bool {
+		mu.Lock()
+		if l != checkedList || l.Version() != checkedVersion {
+			checkedList, checkedVersion = l, l.Version()
+			anyWordMatch = slices.ContainsFunc(l.Tasks(), func(t *todotxt.Item) bool {
+				return word(l, t)
+			})
+		}
+		useWordSearch := anyWordMatch
+		mu.Unlock()
+		if useWordSearch {
+			return word(l, i)
+		}
+		return fuzzy(l, i)
+	}
+}
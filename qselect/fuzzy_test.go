@@ -0,0 +1,79 @@
+package qselect
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FuzzyScore(t *testing.T) {
+	testCases := map[string]struct {
+		pattern string
+		text    string
+		match   bool
+	}{
+		"subsequence matches":          {pattern: "wrtrpt", text: "write report", match: true},
+		"matching is case-insensitive": {pattern: "WR", text: "write Report", match: true},
+		"order matters":                {pattern: "rw", text: "write", match: false},
+		"all characters are required":  {pattern: "writes", text: "write", match: false},
+		"empty pattern matches":        {pattern: "", text: "anything", match: true},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, ok := FuzzyScore(tc.pattern, tc.text)
+			assert.Equal(t, tc.match, ok)
+		})
+	}
+}
+
+func Test_FuzzyScoreRanking(t *testing.T) {
+	better := [][2]string{
+		// {better match, worse match}
+		{"write report", "wait for the reporting tool"},
+		{"report", "remove plan"},
+		{"fooBar", "foobar"},
+	}
+	patterns := []string{"wrtrpt", "rep", "b"}
+	for i, texts := range better {
+		s1, ok1 := FuzzyScore(patterns[i], texts[0])
+		s2, ok2 := FuzzyScore(patterns[i], texts[1])
+		assert.True(t, ok1 && ok2)
+		assert.Greater(t, s1, s2, "%s should match %q better than %q", patterns[i], texts[0], texts[1])
+	}
+}
+
+func Test_FuzzyScoreOfConsidersProjectsAndContexts(t *testing.T) {
+	list := listFromString(t, `
+	a task +kitchen @home due:2022-01-01
+	`)
+	_, ok := FuzzyScoreOf("ktchn", list.GetLine(1))
+	assert.True(t, ok)
+	_, ok = FuzzyScoreOf("hme", list.GetLine(1))
+	assert.True(t, ok)
+	_, ok = FuzzyScoreOf("due", list.GetLine(1))
+	assert.False(t, ok, "tags are not part of the clean description")
+}
+
+func Test_CompileQueryFallsBackToFuzzySearch(t *testing.T) {
+	list := listFromString(t, `
+	write report
+	water the plants
+	`)
+	fuzzy, err := CompileQuery("wrtrpt")
+	assert.Nil(t, err)
+	assert.Equal(t, []*todotxt.Item{list.GetLine(1)}, fuzzy.Filter(list))
+
+	word, err := CompileQuery("wat")
+	assert.Nil(t, err)
+	assert.Equal(t, []*todotxt.Item{list.GetLine(2)}, word.Filter(list), "word search has precedence over fuzzy search")
+}
+
+func Test_WordSearchDoesNotFallBackToFuzzySearch(t *testing.T) {
+	list := listFromString(t, `
+	write report
+	`)
+	word, err := CompileWordSearch("wrtrpt")
+	assert.Nil(t, err)
+	assert.Empty(t, word.Filter(list))
+}
@@ -0,0 +1,62 @@
+package qselect
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+var ErrIdsDisabled = errors.New("stable task ids are not enabled. Configure ids.tag to use them")
+
+var idTag string
+
+// RegisterIdTag enables id based selections and the id function for
+// the given tag. An empty tag disables them again.
+func RegisterIdTag(tag string) {
+	idTag = tag
+	if tag == "" {
+		delete(functions, "id")
+		return
+	}
+	functions["id"] = queryFunc{
+		fn:               id,
+		argTypes:         []DType{QItem},
+		resultType:       QString,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     false,
+	}
+}
+
+func id(args []any) any {
+	item := args[0].(*todotxt.Item)
+	return idOf(item)
+}
+
+func idOf(item *todotxt.Item) string {
+	if values := item.TagValues(idTag); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func compileIdSelection(query string) (Func, error) {
+	if idTag == "" {
+		return nil, ErrIdsDisabled
+	}
+	ids := make([]string, 0)
+	for _, i := range strings.Split(query, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			ids = append(ids, i)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("empty id list is not a valid query")
+	}
+	return func(l *todotxt.List, i *todotxt.Item) bool {
+		id := idOf(i)
+		return id != "" && slices.Contains(ids, id)
+	}, nil
+}
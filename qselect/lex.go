@@ -43,6 +43,8 @@ const (
 	itemCtxMatch
 	itemPlus
 	itemMinus
+	itemPipe
+	itemMatch
 )
 
 const eof = -1
@@ -56,6 +58,7 @@ type lexer struct {
 	atEOF      bool
 	item       item
 	parenDepth int
+	braceDepth int
 }
 
 func (l *lexer) next() rune {
@@ -194,12 +197,20 @@ func lexQuery(l *lexer) stateFunc {
 		l.backup()
 		return l.emit(itemGt)
 	case r == '=':
-		if l.next() != '=' {
-			return l.errorf("expected ==")
+		switch l.next() {
+		case '=':
+			return l.emit(itemEq)
+		case '~':
+			return l.emit(itemMatch)
+		default:
+			return l.errorf("expected == or =~")
 		}
-		return l.emit(itemEq)
 	case r == '|':
 		if l.next() != '|' {
+			if l.braceDepth > 0 { // Within braces a single | separates the element of a comprehension
+				l.backup()
+				return l.emit(itemPipe)
+			}
 			return l.errorf("expected ||")
 		}
 		return l.emit(itemOr)
@@ -213,12 +224,18 @@ func lexQuery(l *lexer) stateFunc {
 		return l.emit(itemComma)
 	case r == '(' || r == '{':
 		l.parenDepth++
+		if r == '{' {
+			l.braceDepth++
+		}
 		return l.emit(itemLeftParen)
 	case r == ')' || r == '}':
 		l.parenDepth--
 		if l.parenDepth < 0 {
 			return l.errorf("unexpected right paren")
 		}
+		if r == '}' {
+			l.braceDepth--
+		}
 		return l.emit(itemRightParen)
 	case r == ':':
 		return l.emit(itemColon)
@@ -308,7 +325,22 @@ func lexContextMatcher(l *lexer) stateFunc {
 func lexIntOrDuration(l *lexer) stateFunc {
 	digits := "0123456789"
 	l.acceptRun(digits)
-	duration := l.accept("dwmy")
+	duration := l.accept("dwmyh")
+	if duration && l.input[l.pos-1] == 'm' && strings.HasPrefix(l.input[l.pos:], "in") {
+		// minutes
+		l.pos += len("in")
+	}
+	if !duration && strings.HasPrefix(l.input[l.pos:], "bd") {
+		// business days
+		l.pos += len("bd")
+		duration = true
+	}
+	if duration && l.accept("@") {
+		// anchor like @eom or @2tue
+		for isAlphaNumeric(l.peek()) {
+			l.next()
+		}
+	}
 	if isAlphaNumeric(l.peek()) {
 		return l.errorf("Unexpected character at end of integer")
 	}
@@ -65,6 +65,14 @@ func Test_lexOnValidQueries(t *testing.T) {
 			query:          "R{x}",
 			expectedTokens: []itemType{itemIdent, itemLeftParen, itemIdent, itemRightParen},
 		},
+		"single pipe within curly braces": {
+			query:          "{x | x in items}",
+			expectedTokens: []itemType{itemLeftParen, itemIdent, itemPipe, itemIdent, itemIn, itemIdent, itemRightParen},
+		},
+		"regex match": {
+			query:          `description=~"^foo"`,
+			expectedTokens: []itemType{itemIdent, itemMatch, itemString},
+		},
 		"and can be used for &&": {
 			query:          "true and false",
 			expectedTokens: []itemType{itemBool, itemAnd, itemBool},
@@ -93,6 +101,14 @@ func Test_lexOnValidQueries(t *testing.T) {
 			query:          `5d`,
 			expectedTokens: []itemType{itemDuration},
 		},
+		"business day duration": {
+			query:          `5bd+1`,
+			expectedTokens: []itemType{itemDuration, itemPlus, itemInt},
+		},
+		"anchored duration": {
+			query:          `1m@2tue + 0d@eom`,
+			expectedTokens: []itemType{itemDuration, itemPlus, itemDuration},
+		},
 	}
 
 	for name, tc := range testCases {
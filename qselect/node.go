@@ -4,9 +4,11 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Fabian-G/quest/qduration"
@@ -19,19 +21,25 @@ const (
 	QError       DType = "error"
 	QInt         DType = "int"
 	QDate        DType = "date"
+	QDateTime    DType = "datetime"
 	QPriority    DType = "priority"
 	QDuration    DType = "duration"
 	QString      DType = "string"
 	QStringSlice DType = "[]string"
+	QIntSlice    DType = "[]int"
+	QDateSlice   DType = "[]date"
 	QBool        DType = "bool"
 	QItem        DType = "item"
 	QItemSlice   DType = "[]item"
 )
 
-var AllDTypes = []DType{QInt, QDate, QDuration, QString, QStringSlice, QBool, QItem, QItemSlice, QPriority}
+// DateTimeLayout is the format of datetime values (e.g. in tags). It does not contain spaces, so that it can be used as tag value.
+const DateTimeLayout = "2006-01-02T15:04"
+
+var AllDTypes = []DType{QInt, QDate, QDateTime, QDuration, QString, QStringSlice, QIntSlice, QDateSlice, QBool, QItem, QItemSlice, QPriority}
 
 func (d DType) isSliceType() bool {
-	return slices.Contains([]DType{QStringSlice, QItemSlice}, d)
+	return slices.Contains([]DType{QStringSlice, QItemSlice, QIntSlice, QDateSlice}, d)
 }
 
 func (d DType) sliceTypeToItemType() DType {
@@ -40,6 +48,24 @@ func (d DType) sliceTypeToItemType() DType {
 		return QItem
 	case QStringSlice:
 		return QString
+	case QIntSlice:
+		return QInt
+	case QDateSlice:
+		return QDate
+	}
+	return QError
+}
+
+func (d DType) itemTypeToSliceType() DType {
+	switch d {
+	case QItem:
+		return QItemSlice
+	case QString:
+		return QStringSlice
+	case QInt:
+		return QIntSlice
+	case QDate:
+		return QDateSlice
 	}
 	return QError
 }
@@ -52,6 +78,24 @@ func toAnySlice[S ~[]E, E any](s S) []any {
 	return r
 }
 
+// PositionError is an error that refers to a position (byte offset) in the query
+type PositionError struct {
+	Pos int
+	Err error
+}
+
+func (p PositionError) Error() string {
+	return fmt.Sprintf("%s at position %d", p.Err, p.Pos)
+}
+
+func (p PositionError) Unwrap() error {
+	return p.Err
+}
+
+func errorAt(pos int, format string, args ...any) error {
+	return PositionError{Pos: pos, Err: fmt.Errorf(format, args...)}
+}
+
 type varMap map[string]any
 type idSet map[string]DType
 
@@ -65,6 +109,7 @@ type allQuant struct {
 	boundId    string
 	child      node
 	collection node
+	pos        int
 }
 
 func (a *allQuant) eval(alpha varMap) any {
@@ -89,7 +134,7 @@ func (a *allQuant) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if !collectionType.isSliceType() {
-		return QError, fmt.Errorf("can not use non slice type %s as collection in quantifier", collectionType)
+		return QError, errorAt(a.pos, "can not use non slice type %s as collection in quantifier", collectionType)
 	}
 	if _, ok := knownIds[a.boundId]; !ok {
 		knownIds[a.boundId] = collectionType.sliceTypeToItemType()
@@ -100,7 +145,7 @@ func (a *allQuant) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if childType != QBool {
-		return QError, fmt.Errorf("can not apply all quantor on expression of type %s", childType)
+		return QError, errorAt(a.pos, "can not apply all quantor on expression of type %s", childType)
 	}
 	return QBool, nil
 }
@@ -109,6 +154,7 @@ type existQuant struct {
 	boundId    string
 	child      node
 	collection node
+	pos        int
 }
 
 func (e *existQuant) eval(alpha varMap) any {
@@ -133,7 +179,7 @@ func (e *existQuant) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if !collectionType.isSliceType() {
-		return QError, fmt.Errorf("can not use non slice type %s as collection in quantifier", collectionType)
+		return QError, errorAt(e.pos, "can not use non slice type %s as collection in quantifier", collectionType)
 	}
 	if _, ok := knownIds[e.boundId]; !ok {
 		knownIds[e.boundId] = collectionType.sliceTypeToItemType()
@@ -144,7 +190,266 @@ func (e *existQuant) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if childType != QBool {
-		return QError, fmt.Errorf("can not apply exists quantor on expression of type %s", childType)
+		return QError, errorAt(e.pos, "can not apply exists quantor on expression of type %s", childType)
+	}
+	return QBool, nil
+}
+
+type lambda struct {
+	boundId string
+	body    node
+	pos     int
+}
+
+func (l *lambda) eval(alpha varMap) any {
+	panic("lambda must only be evaluated in the context of an aggregate function")
+}
+
+func (l *lambda) String() string {
+	return fmt.Sprintf("%s: %s", l.boundId, l.body.String())
+}
+
+func (l *lambda) validate(knownIds idSet) (DType, error) {
+	return QError, errorAt(l.pos, "lambdas must only occur as argument of an aggregate function")
+}
+
+// validateWith validates the body of the lambda with its bound id having the given type.
+func (l *lambda) validateWith(knownIds idSet, boundType DType) (DType, error) {
+	prevType, wasKnown := knownIds[l.boundId]
+	knownIds[l.boundId] = boundType
+	defer func() {
+		if wasKnown {
+			knownIds[l.boundId] = prevType
+		} else {
+			delete(knownIds, l.boundId)
+		}
+	}()
+	return l.body.validate(knownIds)
+}
+
+func (l *lambda) apply(alpha varMap, value any) any {
+	prevValue := alpha[l.boundId]
+	defer func() { alpha[l.boundId] = prevValue }()
+	alpha[l.boundId] = value
+	return l.body.eval(alpha)
+}
+
+type aggregate struct {
+	name       string
+	fn         aggregateFunc
+	collection node
+	lambda     *lambda // may be nil
+	valueType  DType   // This field is set by validate()
+	pos        int
+}
+
+func (a *aggregate) eval(alpha varMap) any {
+	values := a.collection.eval(alpha).([]any)
+	if a.lambda != nil {
+		mapped := make([]any, 0, len(values))
+		for _, v := range values {
+			result := a.lambda.apply(alpha, v)
+			switch {
+			case !a.fn.predicate:
+				mapped = append(mapped, result)
+			case result.(bool):
+				mapped = append(mapped, v)
+			}
+		}
+		values = mapped
+	}
+	return a.fn.fn(values, a.valueType)
+}
+
+func (a *aggregate) String() string {
+	if a.lambda == nil {
+		return fmt.Sprintf("%s(%s)", a.name, a.collection.String())
+	}
+	return fmt.Sprintf("%s(%s, %s)", a.name, a.collection.String(), a.lambda.String())
+}
+
+func (a *aggregate) validate(knownIds idSet) (DType, error) {
+	collectionType, err := a.collection.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	if !collectionType.isSliceType() {
+		return QError, errorAt(a.pos, "can not use non slice type %s as collection in %s", collectionType, a.name)
+	}
+	a.valueType = collectionType.sliceTypeToItemType()
+	if a.lambda != nil {
+		bodyType, err := a.lambda.validateWith(knownIds, a.valueType)
+		if err != nil {
+			return QError, err
+		}
+		switch {
+		case a.fn.predicate && bodyType != QBool:
+			return QError, errorAt(a.pos, "the lambda of %s must be of type bool, got: %s", a.name, bodyType)
+		case !a.fn.predicate:
+			a.valueType = bodyType
+		}
+	}
+	resultType, err := a.fn.validate(a.valueType)
+	if err != nil {
+		return QError, errorAt(a.pos, "can not apply %s: %w", a.name, err)
+	}
+	return resultType, nil
+}
+
+type comprehension struct {
+	element    node
+	boundId    string
+	collection node
+	predicate  node // may be nil
+	pos        int
+}
+
+func (c *comprehension) eval(alpha varMap) any {
+	prevValue := alpha[c.boundId]
+	defer func() { alpha[c.boundId] = prevValue }()
+	collection := c.collection.eval(alpha).([]any)
+	result := make([]any, 0, len(collection))
+	for _, item := range collection {
+		alpha[c.boundId] = item
+		if c.predicate != nil && !c.predicate.eval(alpha).(bool) {
+			continue
+		}
+		result = append(result, c.element.eval(alpha))
+	}
+	return result
+}
+
+func (c *comprehension) String() string {
+	if c.predicate == nil {
+		return fmt.Sprintf("{%s | %s in %s}", c.element.String(), c.boundId, c.collection.String())
+	}
+	return fmt.Sprintf("{%s | %s in %s: %s}", c.element.String(), c.boundId, c.collection.String(), c.predicate.String())
+}
+
+func (c *comprehension) validate(knownIds idSet) (DType, error) {
+	collectionType, err := c.collection.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	if !collectionType.isSliceType() {
+		return QError, errorAt(c.pos, "can not use non slice type %s as collection in comprehension", collectionType)
+	}
+	prevType, wasKnown := knownIds[c.boundId]
+	knownIds[c.boundId] = collectionType.sliceTypeToItemType()
+	defer func() {
+		if wasKnown {
+			knownIds[c.boundId] = prevType
+		} else {
+			delete(knownIds, c.boundId)
+		}
+	}()
+	if c.predicate != nil {
+		predicateType, err := c.predicate.validate(knownIds)
+		if err != nil {
+			return QError, err
+		}
+		if predicateType != QBool {
+			return QError, errorAt(c.pos, "the condition of a comprehension must be of type bool, got: %s", predicateType)
+		}
+	}
+	elementType, err := c.element.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	resultType := elementType.itemTypeToSliceType()
+	if resultType == QError {
+		return QError, errorAt(c.pos, "can not build a collection of type %s", elementType)
+	}
+	return resultType, nil
+}
+
+type membership struct {
+	element     node
+	collection  node
+	elementType DType // This field is set by validate()
+	pos         int
+}
+
+func (m *membership) eval(alpha varMap) any {
+	element := m.element.eval(alpha)
+	for _, item := range m.collection.eval(alpha).([]any) {
+		if compare(itemEq, m.elementType, m.elementType, element, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *membership) String() string {
+	return fmt.Sprintf("(%s in %s)", m.element.String(), m.collection.String())
+}
+
+func (m *membership) validate(knownIds idSet) (DType, error) {
+	elementType, err := m.element.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	collectionType, err := m.collection.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	if collectionType.sliceTypeToItemType() != elementType {
+		return QError, errorAt(m.pos, "can not test if %s is in %s", elementType, collectionType)
+	}
+	m.elementType = elementType
+	return QBool, nil
+}
+
+type regexMatch struct {
+	str      node
+	pattern  node
+	compiled *regexp.Regexp // Only set by validate() if the pattern is a constant
+	dynamic  sync.Map       // Compiled non constant patterns. Invalid ones are stored as nil.
+	pos      int
+}
+
+func (r *regexMatch) eval(alpha varMap) any {
+	re := r.compiled
+	if re == nil {
+		re = r.compileDynamic(r.pattern.eval(alpha).(string))
+	}
+	// Invalid patterns (which can only be detected at evaluation time if they are not constant) match nothing
+	return re != nil && re.MatchString(r.str.eval(alpha).(string))
+}
+
+func (r *regexMatch) compileDynamic(pattern string) *regexp.Regexp {
+	if re, ok := r.dynamic.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	r.dynamic.Store(pattern, re)
+	return re
+}
+
+func (r *regexMatch) String() string {
+	return fmt.Sprintf("(%s =~ %s)", r.str.String(), r.pattern.String())
+}
+
+func (r *regexMatch) validate(knownIds idSet) (DType, error) {
+	strType, err := r.str.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	patternType, err := r.pattern.validate(knownIds)
+	if err != nil {
+		return QError, err
+	}
+	if strType != QString || patternType != QString {
+		return QError, errorAt(r.pos, "can not match %s against %s", strType, patternType)
+	}
+	if constant, ok := r.pattern.(*stringConst); ok {
+		r.compiled, err = regexp.Compile(constant.eval(nil).(string))
+		if err != nil {
+			return QError, errorAt(r.pos, "invalid regular expression %s: %w", constant.String(), err)
+		}
 	}
 	return QBool, nil
 }
@@ -152,6 +457,7 @@ func (e *existQuant) validate(knownIds idSet) (DType, error) {
 type impl struct {
 	leftChild  node
 	rightChild node
+	pos        int
 }
 
 func (i *impl) eval(alpha varMap) any {
@@ -172,7 +478,7 @@ func (i *impl) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if c1 != QBool || c2 != QBool {
-		return QError, fmt.Errorf("can not apply implication on (%s, %s)", c1, c2)
+		return QError, errorAt(i.pos, "can not apply implication on (%s, %s)", c1, c2)
 	}
 	return QBool, nil
 }
@@ -180,6 +486,7 @@ func (i *impl) validate(knownIds idSet) (DType, error) {
 type and struct {
 	leftChild  node
 	rightChild node
+	pos        int
 }
 
 func (a *and) eval(alpha varMap) any {
@@ -200,7 +507,7 @@ func (a *and) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if c1 != QBool || c2 != QBool {
-		return QError, fmt.Errorf("can not apply conjunction on (%s, %s)", c1, c2)
+		return QError, errorAt(a.pos, "can not apply conjunction on (%s, %s)", c1, c2)
 	}
 	return QBool, nil
 }
@@ -210,16 +517,18 @@ type plus struct {
 	rightChild node
 	lType      DType
 	rType      DType
+	resultType DType
+	pos        int
 }
 
 func (p *plus) eval(alpha varMap) any {
 	switch p.lType {
 	case QInt:
 		return p.leftChild.eval(alpha).(int) + p.rightChild.eval(alpha).(int)
-	case QDate:
-		return p.rightChild.eval(alpha).(qduration.Duration).AddTo(p.leftChild.eval(alpha).(time.Time))
+	case QDate, QDateTime:
+		return asType(p.resultType, p.rightChild.eval(alpha).(qduration.Duration).AddTo(p.leftChild.eval(alpha).(time.Time)))
 	case QDuration:
-		return p.leftChild.eval(alpha).(qduration.Duration).AddTo(p.rightChild.eval(alpha).(time.Time))
+		return asType(p.resultType, p.leftChild.eval(alpha).(qduration.Duration).AddTo(p.rightChild.eval(alpha).(time.Time)))
 	default:
 		panic("plus validation misses a case")
 	}
@@ -241,16 +550,41 @@ func (p *plus) validate(knownIds idSet) (DType, error) {
 	}
 	switch {
 	case p.lType == QInt && p.rType == QInt:
-		return QInt, nil
-	case p.lType == QDuration && p.rType == QDate || p.lType == QDate && p.rType == QDuration:
-		return QDate, nil
+		p.resultType = QInt
+	case p.lType == QDuration && p.rType == QDate:
+		p.resultType = dateArithmeticType(p.leftChild)
+	case p.lType == QDate && p.rType == QDuration:
+		p.resultType = dateArithmeticType(p.rightChild)
+	case p.lType == QDuration && p.rType == QDateTime || p.lType == QDateTime && p.rType == QDuration:
+		p.resultType = QDateTime
 	default:
-		return QError, fmt.Errorf("can not add %s and %s", p.lType, p.rType)
+		return QError, errorAt(p.pos, "can not add %s and %s", p.lType, p.rType)
+	}
+	return p.resultType, nil
+}
+
+// dateArithmeticType is the type of a date plus or minus duration. Constant durations of hours or minutes
+// result in a datetime. Other durations result in a date, which is why the time of day of the result is dropped.
+func dateArithmeticType(duration node) DType {
+	if c, ok := duration.(*durationConst); ok {
+		if d, err := qduration.Parse(c.val); err == nil && d.IsSubDay() {
+			return QDateTime
+		}
+	}
+	return QDate
+}
+
+// asType truncates t to the day if it is supposed to be a date
+func asType(dType DType, t time.Time) time.Time {
+	if dType == QDate {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 	}
+	return t
 }
 
 type negativeSign struct {
 	child node
+	pos   int
 }
 
 func (n *negativeSign) eval(alpha varMap) any {
@@ -267,7 +601,7 @@ func (n *negativeSign) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if cType != QInt {
-		return QError, fmt.Errorf("can not apply negative sign to %s", cType)
+		return QError, errorAt(n.pos, "can not apply negative sign to %s", cType)
 	}
 	return QInt, nil
 }
@@ -277,14 +611,16 @@ type minus struct {
 	rightChild node
 	lType      DType
 	rType      DType
+	resultType DType
+	pos        int
 }
 
 func (m *minus) eval(alpha varMap) any {
 	switch m.lType {
 	case QInt:
 		return m.leftChild.eval(alpha).(int) - m.rightChild.eval(alpha).(int)
-	case QDate:
-		return m.rightChild.eval(alpha).(qduration.Duration).SubFrom(m.leftChild.eval(alpha).(time.Time))
+	case QDate, QDateTime:
+		return asType(m.resultType, m.rightChild.eval(alpha).(qduration.Duration).SubFrom(m.leftChild.eval(alpha).(time.Time)))
 	default:
 		panic("minus validation misses a case")
 	}
@@ -306,12 +642,15 @@ func (m *minus) validate(knownIds idSet) (DType, error) {
 	}
 	switch {
 	case m.lType == QInt && m.rType == QInt:
-		return QInt, nil
+		m.resultType = QInt
 	case m.lType == QDate && m.rType == QDuration:
-		return QDate, nil
+		m.resultType = dateArithmeticType(m.rightChild)
+	case m.lType == QDateTime && m.rType == QDuration:
+		m.resultType = QDateTime
 	default:
-		return QError, fmt.Errorf("can not subtract %s from %s", m.rType, m.lType)
+		return QError, errorAt(m.pos, "can not subtract %s from %s", m.rType, m.lType)
 	}
+	return m.resultType, nil
 }
 
 type comparison struct {
@@ -320,6 +659,7 @@ type comparison struct {
 	rType      DType
 	leftChild  node
 	rightChild node
+	pos        int
 }
 
 func (e *comparison) eval(alpha varMap) any {
@@ -338,21 +678,21 @@ func (e *comparison) validate(knownIds idSet) (DType, error) {
 	if err != nil {
 		return QError, err
 	}
-	if leftType != rightType {
-		return QError, fmt.Errorf("can not compare %s with %s", leftType, rightType)
+	if leftType != rightType && !(isTimeType(leftType) && isTimeType(rightType)) {
+		return QError, errorAt(e.pos, "can not compare %s with %s", leftType, rightType)
 	}
 	if leftType.isSliceType() || rightType.isSliceType() {
-		return QError, errors.New("comparing slice types is not allowed")
+		return QError, errorAt(e.pos, "comparing slice types is not allowed")
 	}
 	if leftType == QItem && e.comparator != itemEq {
-		return QError, errors.New("items can only be compared using ==")
+		return QError, errorAt(e.pos, "items can only be compared using ==")
 	}
 	if leftType == QBool && e.comparator != itemEq {
-		return QError, errors.New("bool values can only be compared using ==")
+		return QError, errorAt(e.pos, "bool values can only be compared using ==")
 	}
-	allowedTypes := []DType{QString, QItem, QDate, QInt, QBool, QPriority}
+	allowedTypes := []DType{QString, QItem, QDate, QDateTime, QInt, QBool, QPriority}
 	if !slices.Contains(allowedTypes, leftType) || !slices.Contains(allowedTypes, rightType) {
-		return QError, fmt.Errorf("can not compare %s with %s. Allowed types are: %v", leftType, rightType, allowedTypes)
+		return QError, errorAt(e.pos, "can not compare %s with %s. Allowed types are: %v", leftType, rightType, allowedTypes)
 	}
 	e.lType = leftType
 	e.rType = rightType
@@ -367,7 +707,7 @@ func compare(op itemType, t1 DType, t2 DType, left, right any) bool {
 		return compareComparable(op, v1, v2)
 	case QItem:
 		return left == right // Only eq comparison allowed. Wrong usage is already caught in validaton
-	case QDate:
+	case QDate, QDateTime:
 		v1 := left.(time.Time)
 		v2 := right.(time.Time)
 		return compareDates(op, v1, v2)
@@ -388,6 +728,12 @@ func compare(op itemType, t1 DType, t2 DType, left, right any) bool {
 	}
 }
 
+// isTimeType reports whether values of t are represented as time.Time. Dates and datetimes can be compared with each other,
+// in which case a date is treated as midnight of that day.
+func isTimeType(t DType) bool {
+	return t == QDate || t == QDateTime
+}
+
 func compareComparable[E cmp.Ordered](op itemType, s1, s2 E) bool {
 	switch op {
 	case itemEq:
@@ -441,6 +787,7 @@ func (e *comparison) String() string {
 type or struct {
 	leftChild  node
 	rightChild node
+	pos        int
 }
 
 func (o *or) eval(alpha varMap) any {
@@ -461,13 +808,14 @@ func (o *or) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if c1 != QBool || c2 != QBool {
-		return QError, fmt.Errorf("can not apply disjunction on (%s, %s)", c1, c2)
+		return QError, errorAt(o.pos, "can not apply disjunction on (%s, %s)", c1, c2)
 	}
 	return QBool, nil
 }
 
 type not struct {
 	child node
+	pos   int
 }
 
 func (n *not) eval(alpha varMap) any {
@@ -484,17 +832,22 @@ func (n *not) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if c != QBool {
-		return QError, fmt.Errorf("can not apply not on %s", c)
+		return QError, errorAt(n.pos, "can not apply not on %s", c)
 	}
 	return QBool, nil
 }
 
 type stringConst struct {
-	val string
+	val      string
+	unquoted any // set during validation, so that eval does not allocate
+	pos      int
 }
 
 func (s *stringConst) eval(alpha varMap) any {
-	return s.val[1 : len(s.val)-1]
+	if s.unquoted == nil {
+		return s.val[1 : len(s.val)-1]
+	}
+	return s.unquoted
 }
 
 func (s *stringConst) String() string {
@@ -502,11 +855,13 @@ func (s *stringConst) String() string {
 
 }
 func (s *stringConst) validate(knownIds idSet) (DType, error) {
+	s.unquoted = s.val[1 : len(s.val)-1]
 	return QString, nil
 }
 
 type intConst struct {
 	val string
+	pos int
 }
 
 func (i *intConst) eval(alpha varMap) any {
@@ -520,13 +875,14 @@ func (i *intConst) String() string {
 
 func (i *intConst) validate(knownIds idSet) (DType, error) {
 	if _, err := strconv.Atoi(i.val); err != nil {
-		return QError, fmt.Errorf("could not parse integer constant: %s", i.val)
+		return QError, errorAt(i.pos, "could not parse integer constant: %s", i.val)
 	}
 	return QInt, nil
 }
 
 type durationConst struct {
 	val string
+	pos int
 }
 
 func (d *durationConst) eval(alpha varMap) any {
@@ -540,13 +896,14 @@ func (d *durationConst) String() string {
 
 func (d *durationConst) validate(knownIds idSet) (DType, error) {
 	if _, err := qduration.Parse(d.val); err != nil {
-		return QError, fmt.Errorf("could not parse duration constant: %s", d.val)
+		return QError, errorAt(d.pos, "could not parse duration constant: %s", d.val)
 	}
 	return QDuration, nil
 }
 
 type boolConst struct {
 	val string
+	pos int
 }
 
 func (b *boolConst) eval(alpha varMap) any {
@@ -560,13 +917,14 @@ func (b *boolConst) String() string {
 
 func (b *boolConst) validate(knownIds idSet) (DType, error) {
 	if _, err := strconv.ParseBool(b.val); err != nil {
-		return QError, fmt.Errorf("could not parse boolean constant: %s", b.val)
+		return QError, errorAt(b.pos, "could not parse boolean constant: %s", b.val)
 	}
 	return QBool, nil
 }
 
 type identifier struct {
 	name string
+	pos  int
 }
 
 func (i *identifier) eval(alpha varMap) any {
@@ -579,7 +937,7 @@ func (i *identifier) String() string {
 
 func (i *identifier) validate(knownIds idSet) (DType, error) {
 	if _, ok := knownIds[i.name]; !ok {
-		return QError, fmt.Errorf("unknown identifier: %s", i.name)
+		return QError, errorAt(i.pos, "unknown identifier: %s", i.name)
 	}
 	return knownIds[i.name], nil
 }
@@ -590,6 +948,7 @@ type call struct {
 	args        *args
 	ifBound     node
 	passThrough bool // if true, all calls will get passed to ifBound. This field is set by validate()
+	pos         int
 }
 
 func (c *call) eval(alpha varMap) any {
@@ -616,16 +975,19 @@ func (c *call) validate(knownIds idSet) (DType, error) {
 		return QError, err
 	}
 	if c.fn.fn == nil {
-		return QError, fmt.Errorf("unknown function with name %s", c.name)
+		return QError, errorAt(c.pos, "unknown function with name %s", c.name)
 	}
 	err = c.fn.validate(argTypes)
 	var missingItemError missingItemError
 	if errors.As(err, &missingItemError) {
-		it := identifier{name: "it"}
+		it := identifier{name: "it", pos: c.pos}
 		c.args.children = slices.Insert[[]node, node](c.args.children, missingItemError.position, &it)
 		return c.validate(knownIds)
 	}
-	return c.fn.resultType, err
+	if err != nil {
+		return QError, errorAt(c.pos, "invalid call of %s: %w", c.name, err)
+	}
+	return c.fn.resultType, nil
 }
 
 type args struct {
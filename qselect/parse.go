@@ -1,7 +1,6 @@
 package qselect
 
 import (
-	"errors"
 	"fmt"
 	"slices"
 )
@@ -19,7 +18,7 @@ func parseQQLTree(query string, expectedFreeVars idSet, expectedResultType DType
 		return nil, err
 	}
 	if parser.lookAhead().typ != eof {
-		return nil, fmt.Errorf("garbage at the end of expression: %s", parser.lookAhead().val)
+		return nil, errorAt(parser.lookAhead().pos, "garbage at the end of expression: %s", parser.lookAhead().val)
 	}
 	t, err := root.validate(expectedFreeVars)
 	if err != nil {
@@ -59,17 +58,17 @@ func (p *parser) parseQuant() (node, error) {
 	p.next()
 	id := p.next()
 	if id.typ != itemIdent {
-		return nil, fmt.Errorf("expected identifier, got: \"%s\" at position %d", id.val, id.pos)
+		return nil, errorAt(id.pos, "expected identifier, got: \"%s\"", id.val)
 	}
 	if in := p.next(); in.typ != itemIn {
-		return nil, fmt.Errorf("expected \"in\", got: \"%s\" at position %d", in.val, id.pos)
+		return nil, errorAt(in.pos, "expected \"in\", got: \"%s\"", in.val)
 	}
 	collection, err := p.parseExp()
 	if err != nil {
 		return nil, err
 	}
 	if c := p.next(); c.typ != itemColon {
-		return nil, fmt.Errorf("expected colon, got: \"%s\" at position %d", c.val, c.pos)
+		return nil, errorAt(c.pos, "expected colon, got: \"%s\"", c.val)
 	}
 	child, err := p.parseExp()
 	if err != nil {
@@ -78,9 +77,9 @@ func (p *parser) parseQuant() (node, error) {
 
 	switch next.typ {
 	case itemAllQuant:
-		return &allQuant{boundId: id.val, collection: collection, child: child}, nil
+		return &allQuant{boundId: id.val, collection: collection, child: child, pos: next.pos}, nil
 	case itemExistQuant:
-		return &existQuant{boundId: id.val, collection: collection, child: child}, nil
+		return &existQuant{boundId: id.val, collection: collection, child: child, pos: next.pos}, nil
 
 	}
 	panic("This statement can not be reached")
@@ -92,7 +91,7 @@ func (p *parser) parseImpl() (node, error) {
 		return nil, err
 	}
 	for next := p.lookAhead(); next.typ == itemImpl; {
-		p.next()
+		op := p.next()
 		rightChild, err := p.parseOr()
 		if err != nil {
 			return nil, err
@@ -101,6 +100,7 @@ func (p *parser) parseImpl() (node, error) {
 		child = &impl{
 			leftChild:  child,
 			rightChild: rightChild,
+			pos:        op.pos,
 		}
 	}
 	return child, nil
@@ -112,7 +112,7 @@ func (p *parser) parseOr() (node, error) {
 		return nil, err
 	}
 	for next := p.lookAhead(); next.typ == itemOr; {
-		p.next()
+		op := p.next()
 		rightChild, err := p.parseAnd()
 		if err != nil {
 			return nil, err
@@ -121,6 +121,7 @@ func (p *parser) parseOr() (node, error) {
 		child = &or{
 			leftChild:  child,
 			rightChild: rightChild,
+			pos:        op.pos,
 		}
 	}
 	return child, nil
@@ -132,7 +133,7 @@ func (p *parser) parseAnd() (node, error) {
 		return nil, err
 	}
 	for next := p.lookAhead(); next.typ == itemAnd; {
-		p.next()
+		op := p.next()
 		rightChild, err := p.parseNot()
 		if err != nil {
 			return nil, err
@@ -141,6 +142,7 @@ func (p *parser) parseAnd() (node, error) {
 		child = &and{
 			leftChild:  child,
 			rightChild: rightChild,
+			pos:        op.pos,
 		}
 	}
 	return child, nil
@@ -160,11 +162,12 @@ func (p *parser) parseNot() (node, error) {
 
 	return &not{
 		child: child,
+		pos:   next.pos,
 	}, nil
 }
 
 func (p *parser) parseComparison() (node, error) {
-	comparisons := []itemType{itemEq, itemLeq, itemLt, itemGeq, itemGt}
+	comparisons := []itemType{itemEq, itemLeq, itemLt, itemGeq, itemGt, itemIn, itemMatch}
 	child, err := p.parseAddSub()
 	if err != nil {
 		return nil, err
@@ -175,10 +178,26 @@ func (p *parser) parseComparison() (node, error) {
 		if err != nil {
 			return nil, err
 		}
-		child = &comparison{
-			comparator: next.typ,
-			leftChild:  child,
-			rightChild: rightChild,
+		switch next.typ {
+		case itemIn:
+			child = &membership{
+				element:    child,
+				collection: rightChild,
+				pos:        next.pos,
+			}
+		case itemMatch:
+			child = &regexMatch{
+				str:     child,
+				pattern: rightChild,
+				pos:     next.pos,
+			}
+		default:
+			child = &comparison{
+				comparator: next.typ,
+				leftChild:  child,
+				rightChild: rightChild,
+				pos:        next.pos,
+			}
 		}
 		next = p.lookAhead()
 	}
@@ -202,11 +221,13 @@ func (p *parser) parseAddSub() (node, error) {
 			child = &plus{
 				leftChild:  child,
 				rightChild: rightChild,
+				pos:        next.pos,
 			}
 		case itemMinus:
 			child = &minus{
 				leftChild:  child,
 				rightChild: rightChild,
+				pos:        next.pos,
 			}
 		}
 		next = p.lookAhead()
@@ -231,6 +252,7 @@ func (p *parser) parseSign() (node, error) {
 	}
 	return &negativeSign{
 		child: child,
+		pos:   next.pos,
 	}, nil
 }
 
@@ -241,21 +263,25 @@ func (p *parser) parsePrimary() (node, error) {
 		p.next()
 		return &stringConst{
 			val: next.val,
+			pos: next.pos,
 		}, nil
 	case itemInt:
 		p.next()
 		return &intConst{
 			val: next.val,
+			pos: next.pos,
 		}, nil
 	case itemDuration:
 		p.next()
 		return &durationConst{
 			val: next.val,
+			pos: next.pos,
 		}, nil
 	case itemBool:
 		p.next()
 		return &boolConst{
 			val: next.val,
+			pos: next.pos,
 		}, nil
 	case itemProjMatch:
 		p.next()
@@ -269,9 +295,12 @@ func (p *parser) parsePrimary() (node, error) {
 		if err != nil {
 			return nil, err
 		}
-		next := p.next()
-		if next.typ != itemRightParen {
-			return nil, errors.New("missing closing parenthesis")
+		if next.val == "{" && p.lookAhead().typ == itemPipe {
+			return p.parseComprehension(child, next.pos)
+		}
+		closing := p.next()
+		if closing.typ != itemRightParen {
+			return nil, errorAt(closing.pos, "missing closing parenthesis for the one at position %d", next.pos)
 		}
 		return child, err
 	case itemAllQuant:
@@ -281,8 +310,44 @@ func (p *parser) parsePrimary() (node, error) {
 	case itemIdent:
 		return p.parseIdentifierOrCall()
 	default:
-		return nil, fmt.Errorf("unexpected token: \"%s\" at position %d", next.val, next.pos)
+		return nil, errorAt(next.pos, "unexpected token: \"%s\"", next.val)
+	}
+}
+
+// parseComprehension parses the rest of "{element | x in collection: predicate}" after the element.
+func (p *parser) parseComprehension(element node, pos int) (node, error) {
+	if pipe := p.next(); pipe.typ != itemPipe {
+		return nil, errorAt(pipe.pos, "expected \"|\", got: \"%s\"", pipe.val)
+	}
+	id := p.next()
+	if id.typ != itemIdent {
+		return nil, errorAt(id.pos, "expected identifier, got: \"%s\"", id.val)
+	}
+	if in := p.next(); in.typ != itemIn {
+		return nil, errorAt(in.pos, "expected \"in\", got: \"%s\"", in.val)
+	}
+	collection, err := p.parseExp()
+	if err != nil {
+		return nil, err
+	}
+	var predicate node
+	if p.lookAhead().typ == itemColon {
+		p.next()
+		predicate, err = p.parseExp()
+		if err != nil {
+			return nil, err
+		}
 	}
+	if rBrace := p.next(); rBrace.val != "}" {
+		return nil, errorAt(rBrace.pos, "expected \"}\", got: \"%s\"", rBrace.val)
+	}
+	return &comprehension{
+		element:    element,
+		boundId:    id.val,
+		collection: collection,
+		predicate:  predicate,
+		pos:        pos,
+	}, nil
 }
 
 func (p *parser) parseIdentifierOrCall() (node, error) {
@@ -293,10 +358,20 @@ func (p *parser) parseIdentifierOrCall() (node, error) {
 		if err != nil {
 			return nil, err
 		}
+		if fn, ok := aggregates[next.val]; ok {
+			return buildAggregate(next.val, fn, args, next.pos)
+		}
+		if next.val == "filter" {
+			return buildFilter(args, next.pos)
+		}
+		if next.val == "matches" {
+			return buildMatches(args, next.pos)
+		}
 		return &call{
 			name: next.val,
 			args: args,
 			fn:   functions[next.val],
+			pos:  next.pos,
 		}, nil
 	} else if fn, ok := functions[next.val]; ok { // This is really a function call without args
 		return &call{
@@ -305,11 +380,14 @@ func (p *parser) parseIdentifierOrCall() (node, error) {
 			args: &args{},
 			ifBound: &identifier{ // Except if this id is bound by a quantifier. In that cas treat it as an identifier
 				name: next.val,
+				pos:  next.pos,
 			},
+			pos: next.pos,
 		}, nil
 	}
 	return &identifier{
 		name: next.val,
+		pos:  next.pos,
 	}, nil
 }
 
@@ -317,7 +395,7 @@ func (p *parser) parseArgs() (*args, error) {
 	var arguments []node
 	lParen := p.next()
 	if lParen.typ != itemLeftParen {
-		return nil, fmt.Errorf("expected opening parenthesis got: \"%s\" at position %d", lParen.val, lParen.pos)
+		return nil, errorAt(lParen.pos, "expected opening parenthesis got: \"%s\"", lParen.val)
 	}
 	if p.lookAhead().typ == itemRightParen {
 		p.next()
@@ -328,6 +406,12 @@ func (p *parser) parseArgs() (*args, error) {
 		if err != nil {
 			return nil, err
 		}
+		if p.lookAhead().typ == itemColon {
+			arg, err = p.parseLambda(arg)
+			if err != nil {
+				return nil, err
+			}
+		}
 		arguments = append(arguments, arg)
 		commaOrParen := p.next()
 		if commaOrParen.typ == itemComma {
@@ -336,13 +420,88 @@ func (p *parser) parseArgs() (*args, error) {
 		if commaOrParen.typ == itemRightParen {
 			break
 		}
-		return nil, fmt.Errorf("expected comma got: \"%s\" at position %d", commaOrParen.val, commaOrParen.pos)
+		return nil, errorAt(commaOrParen.pos, "expected comma got: \"%s\"", commaOrParen.val)
 	}
 	return &args{
 		children: arguments,
 	}, nil
 }
 
+// parseLambda parses the body of a lambda "x: expression" whose bound id has already been parsed.
+func (p *parser) parseLambda(boundId node) (node, error) {
+	var name string
+	switch id := boundId.(type) {
+	case *identifier:
+		name = id.name
+	case *call:
+		if id.ifBound == nil {
+			return nil, errorAt(id.pos, "expected identifier before colon, got: \"%s\"", id.String())
+		}
+		name = id.name
+	default:
+		return nil, errorAt(p.lookAhead().pos, "expected identifier before colon, got: \"%s\"", id.String())
+	}
+	colon := p.next()
+	body, err := p.parseExp()
+	if err != nil {
+		return nil, err
+	}
+	return &lambda{boundId: name, body: body, pos: colon.pos}, nil
+}
+
+func buildAggregate(name string, fn aggregateFunc, args *args, pos int) (node, error) {
+	if len(args.children) == 0 || len(args.children) > 2 {
+		return nil, errorAt(pos, "%s expects a collection and an optional lambda, got %d arguments", name, len(args.children))
+	}
+	if _, ok := args.children[0].(*lambda); ok {
+		return nil, errorAt(pos, "the first argument of %s must be a collection", name)
+	}
+	agg := &aggregate{
+		name:       name,
+		fn:         fn,
+		collection: args.children[0],
+		pos:        pos,
+	}
+	if len(args.children) == 2 {
+		l, ok := args.children[1].(*lambda)
+		if !ok {
+			return nil, errorAt(pos, "the second argument of %s must be a lambda like \"x: done(x)\"", name)
+		}
+		agg.lambda = l
+	}
+	return agg, nil
+}
+
+// buildFilter turns filter(collection, x: predicate) into the comprehension {x | x in collection: predicate}
+func buildFilter(args *args, pos int) (node, error) {
+	if len(args.children) != 2 {
+		return nil, errorAt(pos, "filter expects a collection and a lambda, got %d arguments", len(args.children))
+	}
+	l, ok := args.children[1].(*lambda)
+	if _, isLambda := args.children[0].(*lambda); isLambda || !ok {
+		return nil, errorAt(pos, "filter expects a collection and a lambda like \"x: done(x)\"")
+	}
+	return &comprehension{
+		element:    &identifier{name: l.boundId, pos: l.pos},
+		boundId:    l.boundId,
+		collection: args.children[0],
+		predicate:  l.body,
+		pos:        pos,
+	}, nil
+}
+
+// buildMatches turns matches(str, re) into str =~ re
+func buildMatches(args *args, pos int) (node, error) {
+	if len(args.children) != 2 {
+		return nil, errorAt(pos, "matches expects a string and a regular expression, got %d arguments", len(args.children))
+	}
+	return &regexMatch{
+		str:     args.children[0],
+		pattern: args.children[1],
+		pos:     pos,
+	}, nil
+}
+
 func (p *parser) buildProjMatcher(proj string) (node, error) {
 	query := fmt.Sprintf("(exists p in projects(it): dotPrefix(p, \"%s\"))", proj)
 	parser := *p
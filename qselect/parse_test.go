@@ -97,6 +97,46 @@ func Test_ParseConstructTheTreeCorrectly(t *testing.T) {
 			query:               "5+5-5+5==10",
 			expectedParseResult: "((((5 + 5) - 5) + 5) == 10)",
 		},
+		"aggregate without lambda": {
+			query:               "count(items) > 5",
+			expectedParseResult: "(count(items) > 5)",
+		},
+		"aggregate with lambda": {
+			query:               "count(items, x: done(x) && !done) == 1",
+			expectedParseResult: "(count(items, x: (done(x) && !done(it))) == 1)",
+		},
+		"comprehension": {
+			query:               `exists d in {tag(x, "due") | x in items: done(x)}: d == ""`,
+			expectedParseResult: `(exists d in {tag(x, "due") | x in items: done(x)}: (d == ""))`,
+		},
+		"comprehension without condition": {
+			query:               `count({x | x in items}) == 0`,
+			expectedParseResult: `(count({x | x in items}) == 0)`,
+		},
+		"filter is a comprehension": {
+			query:               `count(filter(items, x: done(x))) == 0`,
+			expectedParseResult: `(count({x | x in items: done(x)}) == 0)`,
+		},
+		"in has the precedence of a comparison": {
+			query:               `"+foo" in projects && done`,
+			expectedParseResult: `(("+foo" in projects(it)) && done(it))`,
+		},
+		"curly braces can still be used as parens": {
+			query:               `{true || false} && true`,
+			expectedParseResult: `((true || false) && true)`,
+		},
+		"regex match has the precedence of a comparison": {
+			query:               `description =~ "^foo" && done`,
+			expectedParseResult: `((description(it) =~ "^foo") && done(it))`,
+		},
+		"matches is a regex match": {
+			query:               `matches(description, "^foo")`,
+			expectedParseResult: `(description(it) =~ "^foo")`,
+		},
+		"lambda can bind function name": {
+			query:               "sum(items, done: line(done)) == 1",
+			expectedParseResult: "(sum(items, done: line(done)) == 1)",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -356,6 +396,182 @@ func Test_eval(t *testing.T) {
 			itemNumber: 1,
 			result:     true,
 		},
+		"count items matching a predicate": {
+			list: listFromString(t, `
+			x a done item
+			an open +foo item
+			another open +foo item
+			`),
+			query:  `count(items, x: !done(x)) == 2 && count(items) == 3`,
+			result: true,
+		},
+		"count strings": {
+			list: listFromString(t, `
+			an item +foo +bar @home
+			`),
+			query:  `count(projects) == 2 && count(contexts, c: c == "@work") == 0`,
+			result: true,
+		},
+		"sum and avg over mapped values": {
+			list: listFromString(t, `
+			a task est:3
+			a task est:4
+			a task without estimate
+			`),
+			query:  `sum(items, x: int(tag(x, "est"))) == 7 && avg(items, x: int(tag(x, "est"))) == 2`,
+			result: true,
+		},
+		"estimate exceeds the average estimate": {
+			list: listFromString(t, `
+			a +foo task est:10
+			a +foo task est:2
+			a +bar task est:100
+			`),
+			query:      `int(tag("est")) > avg(items, x: int(tag(x, "est")))`,
+			itemNumber: 2,
+			result:     false,
+		},
+		"projects with more than one open task": {
+			list: listFromString(t, `
+			a +foo task
+			another +foo task
+			a +bar task
+			`),
+			query:      `exists p in projects: count(items, x: !done(x) && (exists q in projects(x): q == p)) > 1`,
+			itemNumber: 3,
+			result:     false,
+		},
+		"min and max of dates": {
+			list: listFromString(t, `
+			2022-01-05 first
+			2022-01-01 second
+			2022-01-03 third
+			`),
+			query:  `min(items, x: creation(x)) == ymd(2022, 1, 1) && max(items, x: creation(x)) == ymd(2022, 1, 5)`,
+			result: true,
+		},
+		"min and max of strings": {
+			list: listFromString(t, `
+			a task +b +a +c
+			`),
+			query:  `min(projects) == "+a" && max(projects) == "+c"`,
+			result: true,
+		},
+		"in tests membership of strings": {
+			list: listFromString(t, `
+			a task +foo +bar
+			`),
+			query:  `"+foo" in projects && !("+baz" in projects)`,
+			result: true,
+		},
+		"in tests membership of items": {
+			list: listFromString(t, `
+			x a done task
+			an open task
+			`),
+			query:      `it in filter(items, x: !done(x))`,
+			itemNumber: 2,
+			result:     true,
+		},
+		"comprehension maps and filters": {
+			list: listFromString(t, `
+			a task due:2022-01-01 +work
+			a task due:2022-01-05 +work
+			a task due:2022-01-02
+			`),
+			query:  `min({date(tag(x, "due")) | x in items: "+work" in projects(x)}) == ymd(2022, 1, 1) && count({tag(x, "due") | x in items}) == 3`,
+			result: true,
+		},
+		"sum over int comprehension": {
+			list: listFromString(t, `
+			a task est:3 +foo
+			a task est:4
+			a task est:5 +foo
+			`),
+			query:  `sum({int(tag(x, "est")) | x in items: "+foo" in projects(x)}) == 8 && 4 in {int(tag(x, "est")) | x in items}`,
+			result: true,
+		},
+		"comprehension can be nested": {
+			list: listFromString(t, `
+			a task +a +b
+			a task +c
+			`),
+			query:  `count({count(projects(x)) | x in {y | y in items: count(projects(y)) > 1}}) == 1`,
+			result: true,
+		},
+		"comprehension does not leak the bound variable": {
+			list: listFromString(t, `
+			a task
+			`),
+			query:  `exists x in projects: false || count({x | x in items}) == 1`,
+			result: false,
+		},
+		"regex match": {
+			list: listFromString(t, `
+			call Bob at 555-1234
+			`),
+			query:  `description =~ "\d{3}-\d{4}" && !matches(description, "^Bob")`,
+			result: true,
+		},
+		"regex match with dynamic pattern": {
+			list: listFromString(t, `
+			a task pattern:^a\s
+			`),
+			query:  `description =~ tag("pattern")`,
+			result: true,
+		},
+		"regex match with invalid dynamic pattern": {
+			list: listFromString(t, `
+			a task pattern:(unclosed
+			`),
+			query:  `description =~ tag("pattern") || matches(description, tag("pattern"))`,
+			result: false,
+		},
+		"string functions": {
+			list: listFromString(t, `
+			Write Report +work
+			`),
+			query: `lower(description) == "write report +work" && upper("abc") == "ABC" && trim("  a ") == "a" && ` +
+				`startsWith(description, "Write") && endsWith(description, "+work") && !startsWith(description, "write")`,
+			result: true,
+		},
+		"split and len": {
+			list: listFromString(t, `
+			a task tags:a;bb;ccc
+			`),
+			query:  `count(split(tag("tags"), ";")) == 3 && "bb" in split(tag("tags"), ";") && len(tag("tags")) == 8 && len("ä") == 1 && count(split("", ";")) == 0`,
+			result: true,
+		},
+		"datetime arithmetic and comparison": {
+			list: listFromString(t, `
+			an appointment at:2022-02-02T14:30
+			`),
+			query: `datetime(tag("at")) + 90min == datetime("2022-02-02T16:00") && datetime(tag("at")) - 2h > date("2022-02-02") && ` +
+				`day(datetime(tag("at"))) == date("2022-02-02") && datetime("2022-02-02") == date("2022-02-02") && datetime("invalid", now) <= now`,
+			result: true,
+		},
+		"date plus hours is a datetime": {
+			list: listFromString(t, `
+			a task due:2022-02-02
+			`),
+			query:  `date(tag("due")) + 12h == datetime("2022-02-02T12:00") && date(tag("due")) - 90min == datetime("2022-02-01T22:30")`,
+			result: true,
+		},
+		"business days and anchored durations": {
+			list: listFromString(t, `
+			a task due:2023-12-22
+			`),
+			query: `date(tag("due")) + 1bd == date("2023-12-25") && date(tag("due")) - 5bd == date("2023-12-15") && ` +
+				`date(tag("due")) + 0d@eom == date("2023-12-31") && date(tag("due")) + 1m@2tue == date("2024-01-09")`,
+			result: true,
+		},
+		"aggregates of empty collections": {
+			list: listFromString(t, `
+			a task
+			`),
+			query:  `max(projects) == "" && sum(items, x: 0) == 0 && count(items, x: false) == 0 && avg(projects, p: 1) == 0`,
+			result: true,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -395,6 +611,60 @@ func Test_InvalidQuerysResultInParseError(t *testing.T) {
 		"wrong collection type": {
 			query: `exists x in done: done(x)`,
 		},
+		"datetime plus datetime": {
+			query: `now + now == now`,
+		},
+		"date minus datetime": {
+			query: `today - now == today`,
+		},
+		"aggregate over non collection": {
+			query: `count(done) == 1`,
+		},
+		"sum over strings": {
+			query: `sum(projects) == 1`,
+		},
+		"count with non bool lambda": {
+			query: `count(items, x: 1) == 1`,
+		},
+		"min over items": {
+			query: `min(items) == it`,
+		},
+		"lambda outside of aggregate": {
+			query: `done(x: x)`,
+		},
+		"aggregate without lambda as second argument": {
+			query: `count(items, true) == 1`,
+		},
+		"comprehension of non collection": {
+			query: `count({x | x in done}) == 1`,
+		},
+		"comprehension with non bool condition": {
+			query: `count({x | x in items: 1}) == 1`,
+		},
+		"comprehension of bool values": {
+			query: `count({done(x) | x in items}) == 1`,
+		},
+		"in with wrong element type": {
+			query: `1 in projects`,
+		},
+		"filter with wrong arguments": {
+			query: `count(filter(items)) == 1`,
+		},
+		"unclosed comprehension": {
+			query: `count({x | x in items) == 1`,
+		},
+		"invalid regular expression": {
+			query: `description =~ "(unclosed"`,
+		},
+		"invalid regular expression in matches": {
+			query: `matches(description, "[a-")`,
+		},
+		"regex match on non string": {
+			query: `done =~ "true"`,
+		},
+		"lambda bound id is not visible outside": {
+			query: `count(items, x: done(x)) == 1 && done(x)`,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -411,3 +681,44 @@ func listFromString(t *testing.T, list string) *todotxt.List {
 	assert.Nil(t, err)
 	return todotxt.ListOf(l...)
 }
+
+func Test_ErrorsContainThePositionInTheQuery(t *testing.T) {
+	testCases := map[string]struct {
+		query    string
+		position int
+	}{
+		"unknown identifier": {
+			query:    `exists x in items: done(y)`,
+			position: 24,
+		},
+		"type error in comparison": {
+			query:    `done && 1 == "a"`,
+			position: 10,
+		},
+		"unknown function": {
+			query:    `!foo(it)`,
+			position: 1,
+		},
+		"unexpected token": {
+			query:    `done && && done`,
+			position: 8,
+		},
+		"missing closing parenthesis": {
+			query:    `(done && done`,
+			position: 13,
+		},
+		"invalid regular expression": {
+			query:    `description =~ "("`,
+			position: 12,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := CompileQQL(tc.query)
+			var posErr PositionError
+			assert.ErrorAs(t, err, &posErr)
+			assert.Equal(t, tc.position, posErr.Pos)
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package qselect
 
 import (
+	"fmt"
 	"maps"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/Fabian-G/quest/todotxt"
@@ -21,6 +23,45 @@ func (q Func) Filter(l *todotxt.List) []*todotxt.Item {
 	return matches
 }
 
+// FilterParallel is like Filter, but evaluates q on up to workers tasks concurrently.
+// The order of the result is the same as for Filter. All Funcs of this package are safe for concurrent use.
+func (q Func) FilterParallel(l *todotxt.List, workers int) []*todotxt.Item {
+	allTasks := l.Tasks()
+	workers = max(1, min(workers, len(allTasks)))
+	selected := make([]bool, len(allTasks))
+	panics := make(chan any, workers)
+	wg := sync.WaitGroup{}
+	chunkSize := (len(allTasks) + workers - 1) / workers
+	for start := 0; start < len(allTasks); start += chunkSize {
+		end := min(start+chunkSize, len(allTasks))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+				}
+			}()
+			for i := start; i < end; i++ {
+				selected[i] = q(l, allTasks[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	close(panics)
+	// Re-panic in the calling goroutine, so that failing queries behave like they do in Filter
+	if r, ok := <-panics; ok {
+		panic(fmt.Errorf("query evaluation failed: %v", r))
+	}
+	matches := make([]*todotxt.Item, 0)
+	for i, t := range allTasks {
+		if selected[i] {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
 func And(fns ...Func) Func {
 	return func(l *todotxt.List, i *todotxt.Item) bool {
 		for _, fn := range fns {
@@ -36,6 +77,7 @@ var defaultFreeVars = idSet{
 	"it":       QItem,
 	"items":    QItemSlice,
 	"today":    QDate,
+	"now":      QDateTime,
 	"maxInt":   QInt,
 	"minInt":   QInt,
 	"maxDate":  QDate,
@@ -104,15 +146,46 @@ var constants = map[string]any{
 }
 
 func buildFreeVars(universe *todotxt.List, item *todotxt.Item) map[string]any {
+	return buildItemFreeVars(buildListFreeVars(universe), item)
+}
+
+// buildListFreeVars builds the free variables that are the same for all items of the list.
+func buildListFreeVars(universe *todotxt.List) map[string]any {
 	alpha := maps.Clone(constants)
-	alpha["it"] = item
 	alpha["items"] = toAnySlice(universe.Tasks())
+	alpha["_list"] = universe
+	return alpha
+}
+
+func buildItemFreeVars(listFreeVars map[string]any, item *todotxt.Item) map[string]any {
+	alpha := maps.Clone(listFreeVars)
+	alpha["it"] = item
 	now := time.Now()
 	alpha["today"] = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-	alpha["_list"] = universe
+	alpha["now"] = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
 	return alpha
 }
 
+// freeVarCache remembers the free variables of the last list a query was evaluated on.
+// Without the cache a query would be O(n²) for a list of size n, because "items" is built for every item.
+type freeVarCache struct {
+	mu           sync.Mutex
+	list         *todotxt.List
+	version      uint64
+	listFreeVars map[string]any
+}
+
+func (c *freeVarCache) freeVars(universe *todotxt.List, item *todotxt.Item) map[string]any {
+	c.mu.Lock()
+	if c.list != universe || c.version != universe.Version() || c.listFreeVars == nil {
+		c.list, c.version = universe, universe.Version()
+		c.listFreeVars = buildListFreeVars(universe)
+	}
+	listFreeVars := c.listFreeVars
+	c.mu.Unlock()
+	return buildItemFreeVars(listFreeVars, item)
+}
+
 func CompileQuery(query string) (Func, error) {
 	q, err := CompileQQL(query)
 	if err == nil {
@@ -122,7 +195,7 @@ func CompileQuery(query string) (Func, error) {
 	if err == nil {
 		return q, nil
 	}
-	return compileStringSearch(query), nil
+	return compileWordOrFuzzySearch(query), nil
 }
 
 func CompileQQL(query string) (Func, error) {
@@ -130,8 +203,9 @@ func CompileQQL(query string) (Func, error) {
 	if err != nil {
 		return nil, err
 	}
+	cache := &freeVarCache{}
 	evalFunc := func(universe *todotxt.List, it *todotxt.Item) bool {
-		return root.eval(buildFreeVars(universe, it)).(bool)
+		return root.eval(cache.freeVars(universe, it)).(bool)
 	}
 	return evalFunc, nil
 }
@@ -143,3 +217,11 @@ func CompileRange(query string) (Func, error) {
 func CompileWordSearch(query string) (Func, error) {
 	return compileStringSearch(query), nil
 }
+
+func CompileFuzzySearch(query string) (Func, error) {
+	return compileFuzzySearch(query), nil
+}
+
+func CompileIdSelection(query string) (Func, error) {
+	return compileIdSelection(query)
+}
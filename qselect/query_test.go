@@ -0,0 +1,95 @@
+package qselect
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FilterParallelReturnsTheSameResultAsFilter(t *testing.T) {
+	list := buildBenchmarkList(300)
+	query, err := CompileQQL(`!done && (exists i in items: tag(i, "id") == tag(it, "after"))`)
+	assert.Nil(t, err)
+
+	expected := query.Filter(list)
+	assert.NotEmpty(t, expected)
+	for _, workers := range []int{-1, 1, 3, 8, 2000} {
+		assert.Equal(t, expected, query.FilterParallel(list, workers))
+	}
+}
+
+func Test_FilterParallelPropagatesPanics(t *testing.T) {
+	list := buildBenchmarkList(10)
+	var query Func = func(l *todotxt.List, i *todotxt.Item) bool {
+		panic("broken query")
+	}
+
+	assert.Panics(t, func() { query.FilterParallel(list, 4) })
+}
+
+func Test_QueriesNoticeChangesOfTheList(t *testing.T) {
+	list := listFromString(t, `
+	a task
+	`)
+	query, err := CompileQQL(`exists i in items: done(i)`)
+	assert.Nil(t, err)
+	assert.Empty(t, query.Filter(list))
+
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("another task"), todotxt.WithDone(true))))
+	assert.Len(t, query.Filter(list), 2)
+
+	assert.Nil(t, list.GetLine(2).MarkUndone())
+	assert.Empty(t, query.Filter(list))
+}
+
+// buildBenchmarkList builds a list where every task depends on its predecessor and every 10th task is done.
+func buildBenchmarkList(n int) *todotxt.List {
+	items := make([]*todotxt.Item, 0, n)
+	for i := 0; i < n; i++ {
+		desc := fmt.Sprintf("task number %d +project%d @context%d id:%d after:%d due:2023-01-%02d", i, i%10, i%5, i, i-1, i%28+1)
+		items = append(items, todotxt.MustBuildItem(todotxt.WithDescription(desc), todotxt.WithDone(i%10 == 0)))
+	}
+	return todotxt.ListOf(items...)
+}
+
+const benchmarkListSize = 10000
+
+var benchmarkQueries = map[string]string{
+	"simple": `!done && +project1`,
+	"tags":   `date(tag(it, "due")) < date("2023-01-15") && int(tag(it, "id")) > 100`,
+	// The first task is done, so this measures the overhead of providing "items" rather than the quantification itself
+	"items": `!done && exists i in items: done(i)`,
+}
+
+func BenchmarkFilter(b *testing.B) {
+	list := buildBenchmarkList(benchmarkListSize)
+	for name, qql := range benchmarkQueries {
+		query, err := CompileQQL(qql)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				query.Filter(list)
+			}
+		})
+	}
+}
+
+func BenchmarkFilterParallel(b *testing.B) {
+	list := buildBenchmarkList(benchmarkListSize)
+	for name, qql := range benchmarkQueries {
+		query, err := CompileQQL(qql)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				query.FilterParallel(list, runtime.GOMAXPROCS(0))
+			}
+		})
+	}
+}
@@ -63,3 +63,48 @@ func Test_Range(t *testing.T) {
 		})
 	}
 }
+
+func Test_IdSelection(t *testing.T) {
+	qselect.RegisterIdTag("id")
+	defer qselect.RegisterIdTag("")
+	testList := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("T1 id:a1")),
+		todotxt.MustBuildItem(todotxt.WithDescription("T2")),
+		todotxt.MustBuildItem(todotxt.WithDescription("T3 id:c3")),
+	)
+	testCases := map[string]struct {
+		ids             string
+		expectedMatches []string
+	}{
+		"single id": {
+			ids:             "c3",
+			expectedMatches: []string{"T3 id:c3"},
+		},
+		"multiple ids": {
+			ids:             "a1, c3",
+			expectedMatches: []string{"T1 id:a1", "T3 id:c3"},
+		},
+		"unknown id": {
+			ids:             "b2",
+			expectedMatches: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			query, err := qselect.CompileIdSelection(tc.ids)
+			assert.Nil(t, err)
+			matches := query.Filter(testList)
+			descriptions := make([]string, 0, len(matches))
+			for _, t := range matches {
+				descriptions = append(descriptions, t.Description())
+			}
+			assert.Equal(t, tc.expectedMatches, descriptions)
+		})
+	}
+}
+
+func Test_IdSelectionFailsWhenIdsAreDisabled(t *testing.T) {
+	_, err := qselect.CompileIdSelection("a1")
+	assert.ErrorIs(t, err, qselect.ErrIdsDisabled)
+}
@@ -0,0 +1,225 @@
+package qselect
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Fabian-G/quest/qduration"
+	"github.com/Fabian-G/quest/todotxt"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// maxScriptSteps bounds the number of Starlark computation steps per call, so that
+// an endless loop in a script function does not hang quest.
+const maxScriptSteps = 1_000_000
+
+var scriptErrors = struct {
+	sync.Mutex
+	report   func(error)
+	reported map[string]struct{}
+}{
+	report:   func(err error) { fmt.Fprintf(os.Stderr, "Warning: %s\n", err) },
+	reported: make(map[string]struct{}),
+}
+
+// SetScriptErrorReporter sets the function that receives the errors of script functions.
+// A failing script evaluates to the zero value of its result type instead of aborting the query,
+// so only the first error of every function is reported (per reporter). The default reporter writes to stderr.
+func SetScriptErrorReporter(report func(error)) {
+	scriptErrors.Lock()
+	defer scriptErrors.Unlock()
+	scriptErrors.report = report
+	scriptErrors.reported = make(map[string]struct{})
+}
+
+func reportScriptError(name string, err error) {
+	scriptErrors.Lock()
+	defer scriptErrors.Unlock()
+	if _, ok := scriptErrors.reported[name]; ok {
+		return
+	}
+	scriptErrors.reported[name] = struct{}{}
+	scriptErrors.report(err)
+}
+
+// RegisterScriptFunction makes the Starlark function called name, defined in src, available in QQL.
+// The script is executed once at registration. It has no access to the file system, the network or other modules.
+func RegisterScriptFunction(name, src string, inTypes []DType, outType DType) error {
+	thread := &starlark.Thread{Name: name}
+	thread.SetMaxExecutionSteps(maxScriptSteps)
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, name, src, nil)
+	if err != nil {
+		return fmt.Errorf("could not load script of function %s: %w", name, err)
+	}
+	fn, ok := globals[name].(starlark.Callable)
+	if !ok {
+		return fmt.Errorf("the script of function %s does not define a function called %s", name, name)
+	}
+	if outType == QItem || outType == QItemSlice {
+		return fmt.Errorf("type %s can not be returned from scripts", outType)
+	}
+	functions[name] = queryFunc{
+		fn: func(args []any) any {
+			scriptArgs := make(starlark.Tuple, 0, len(args))
+			for i, a := range args {
+				scriptArgs = append(scriptArgs, toStarlark(inTypes[i], a))
+			}
+			thread := &starlark.Thread{Name: name}
+			thread.SetMaxExecutionSteps(maxScriptSteps)
+			result, err := starlark.Call(thread, fn, scriptArgs, nil)
+			if err != nil {
+				reportScriptError(name, fmt.Errorf("script function %s returned an error: %w", name, err))
+				return zeroValue(outType)
+			}
+			value, err := fromStarlark(outType, result)
+			if err != nil {
+				reportScriptError(name, fmt.Errorf("script function %s returned an invalid result: %w", name, err))
+				return zeroValue(outType)
+			}
+			return value
+		},
+		argTypes:         inTypes,
+		resultType:       outType,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     false,
+	}
+	return nil
+}
+
+func toStarlark(t DType, v any) starlark.Value {
+	switch t {
+	case QBool:
+		return starlark.Bool(v.(bool))
+	case QInt:
+		return starlark.MakeInt(v.(int))
+	case QString:
+		return starlark.String(v.(string))
+	case QDate:
+		return starlark.String(v.(time.Time).Format(time.DateOnly))
+	case QDateTime:
+		return starlark.String(v.(time.Time).Format(DateTimeLayout))
+	case QDuration:
+		return starlark.MakeInt(v.(qduration.Duration).Days())
+	case QPriority:
+		return priorityToStarlark(v.(todotxt.Priority))
+	case QItem:
+		return itemToStarlark(v.(*todotxt.Item))
+	case QStringSlice, QIntSlice, QDateSlice, QItemSlice:
+		elements := make([]starlark.Value, 0, len(v.([]any)))
+		for _, e := range v.([]any) {
+			elements = append(elements, toStarlark(t.sliceTypeToItemType(), e))
+		}
+		return starlark.NewList(elements)
+	default:
+		panic(fmt.Errorf("type %s can not be passed to scripts", t))
+	}
+}
+
+func fromStarlark(t DType, v starlark.Value) (any, error) {
+	switch t {
+	case QBool:
+		b, ok := v.(starlark.Bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, but got %s", v.Type())
+		}
+		return bool(b), nil
+	case QInt, QDuration:
+		i, ok := v.(starlark.Int)
+		if !ok {
+			return nil, fmt.Errorf("expected int, but got %s", v.Type())
+		}
+		i64, ok := i.Int64()
+		if !ok {
+			return nil, errors.New("int out of range")
+		}
+		if t == QDuration {
+			return qduration.New(int(i64), qduration.Day), nil
+		}
+		return int(i64), nil
+	case QString, QDate, QDateTime, QPriority:
+		s, ok := v.(starlark.String)
+		if !ok {
+			return nil, fmt.Errorf("expected string, but got %s", v.Type())
+		}
+		switch t {
+		case QDate:
+			return time.Parse(time.DateOnly, string(s))
+		case QDateTime:
+			return time.Parse(DateTimeLayout, string(s))
+		case QPriority:
+			if s == "" {
+				return todotxt.PrioNone, nil
+			}
+			return todotxt.PriorityFromString(string(s))
+		}
+		return string(s), nil
+	case QStringSlice, QIntSlice, QDateSlice:
+		iterable, ok := v.(starlark.Iterable)
+		if !ok {
+			return nil, fmt.Errorf("expected list, but got %s", v.Type())
+		}
+		result := make([]any, 0)
+		iter := iterable.Iterate()
+		defer iter.Done()
+		var e starlark.Value
+		for iter.Next(&e) {
+			element, err := fromStarlark(t.sliceTypeToItemType(), e)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, element)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("type %s can not be returned from scripts", t)
+	}
+}
+
+func itemToStarlark(i *todotxt.Item) starlark.Value {
+	projects := make([]starlark.Value, 0, len(i.Projects()))
+	for _, p := range i.Projects() {
+		projects = append(projects, starlark.String(p.String()))
+	}
+	contexts := make([]starlark.Value, 0, len(i.Contexts()))
+	for _, c := range i.Contexts() {
+		contexts = append(contexts, starlark.String(c.String()))
+	}
+	tags := starlark.NewDict(len(i.Tags()))
+	for _, key := range i.Tags().Keys() {
+		values := make([]starlark.Value, 0, len(i.Tags()[key]))
+		for _, v := range i.Tags()[key] {
+			values = append(values, starlark.String(v))
+		}
+		tags.SetKey(starlark.String(key), starlark.NewList(values))
+	}
+	return starlarkstruct.FromStringDict(starlark.String("item"), starlark.StringDict{
+		"description": starlark.String(i.Description()),
+		"done":        starlark.Bool(i.Done()),
+		"priority":    priorityToStarlark(i.Priority()),
+		"creation":    optionalDateToStarlark(i.CreationDate()),
+		"completion":  optionalDateToStarlark(i.CompletionDate()),
+		"projects":    starlark.NewList(projects),
+		"contexts":    starlark.NewList(contexts),
+		"tags":        tags,
+	})
+}
+
+func priorityToStarlark(p todotxt.Priority) starlark.Value {
+	if p == todotxt.PrioNone {
+		return starlark.String("")
+	}
+	return starlark.String(p.String()[1:2])
+}
+
+func optionalDateToStarlark(d *time.Time) starlark.Value {
+	if d == nil {
+		return starlark.None
+	}
+	return starlark.String(d.Format(time.DateOnly))
+}
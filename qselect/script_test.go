@@ -0,0 +1,104 @@
+package qselect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CanRegisterScriptFunctions(t *testing.T) {
+	err := RegisterScriptFunction("isWork", `
+def isWork(item):
+    return "+work" in item.projects and item.priority in ["A", "B"]
+`, []DType{QItem}, QBool)
+	assert.Nil(t, err)
+	defer func() {
+		delete(functions, "isWork")
+	}()
+
+	list := listFromString(t, `
+	(A) write report +work
+	(C) read mails +work
+	(A) go for a run +sports
+	`)
+
+	query, err := CompileQQL("isWork")
+	assert.Nil(t, err)
+
+	matches := query.Filter(list)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, list.GetLine(1).Description(), matches[0].Description())
+}
+
+func Test_ScriptFunctionsConvertArgumentsAndResults(t *testing.T) {
+	err := RegisterScriptFunction("dueTag", `
+def dueTag(item, fallback):
+    return item.tags.get("due", [fallback])[0]
+`, []DType{QItem, QString}, QDate)
+	assert.Nil(t, err)
+	defer func() {
+		delete(functions, "dueTag")
+	}()
+
+	list := listFromString(t, `
+	a task due:2022-02-01
+	another task
+	`)
+	query, err := CompileQQL(`dueTag(it, "2022-03-01") < date("2022-02-15")`)
+	assert.Nil(t, err)
+
+	matches := query.Filter(list)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, list.GetLine(1).Description(), matches[0].Description())
+}
+
+func Test_ScriptFunctionRegistrationFails(t *testing.T) {
+	testCases := map[string]string{
+		"syntax error":      "def broken(:\n    pass",
+		"function missing":  "def other():\n    return True",
+		"no access to load": "load(\"os\", \"system\")\ndef broken():\n    return True",
+		"endless top level": "x = [i for i in range(1000000000)]\ndef broken():\n    return True",
+	}
+	for name, script := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := RegisterScriptFunction("broken", script, nil, QBool)
+			assert.Error(t, err)
+			assert.NotContains(t, functions, "broken")
+		})
+	}
+}
+
+func Test_FailingScriptFunctionsEvaluateToTheZeroValue(t *testing.T) {
+	testCases := map[string]string{
+		"wrong result type": "def failing(item):\n    return 1",
+		"runtime error":     "def failing(item):\n    return item.tags[\"missing\"][0] == \"x\"",
+		"endless loop":      "def failing(item):\n    for i in range(1000000000):\n        pass\n    return True",
+	}
+	for name, script := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Nil(t, RegisterScriptFunction("failing", script, []DType{QItem}, QBool))
+			defer func() {
+				delete(functions, "failing")
+			}()
+			reported := make([]error, 0)
+			SetScriptErrorReporter(func(err error) { reported = append(reported, err) })
+
+			list := listFromString(t, `
+			a task
+			another task
+			`)
+			query, err := CompileQQL("!failing")
+			assert.Nil(t, err)
+
+			assert.Len(t, query.Filter(list), 2)
+			assert.Len(t, reported, 1, "only the first error is reported")
+			assert.ErrorContains(t, reported[0], "script function failing")
+		})
+	}
+}
+
+func Test_ScriptFunctionsCanNotReturnItems(t *testing.T) {
+	err := RegisterScriptFunction("self", "def self(item):\n    return item", []DType{QItem}, QItem)
+	assert.Error(t, err)
+	assert.NotContains(t, functions, "self")
+}
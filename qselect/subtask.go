@@ -0,0 +1,162 @@
+package qselect
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+var ErrSubtasksWithoutIds = errors.New("subtasks refer to the stable id of their parent. Configure ids.tag to use them")
+
+var subtaskTag string
+
+// RegisterSubtaskTag enables the parent, children and progress functions for
+// the given tag. The tag refers to the id of the parent task (see RegisterIdTag). An empty tag disables them again.
+func RegisterSubtaskTag(tag string) error {
+	if tag != "" && idTag == "" {
+		return ErrSubtasksWithoutIds
+	}
+	subtaskTag = tag
+	if tag == "" {
+		delete(functions, "parent")
+		delete(functions, "children")
+		delete(functions, "progress")
+		return nil
+	}
+	functions["parent"] = queryFunc{
+		fn:               parent,
+		argTypes:         []DType{QItem},
+		resultType:       QItemSlice,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	}
+	functions["children"] = queryFunc{
+		fn:               children,
+		argTypes:         []DType{QItem},
+		resultType:       QItemSlice,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	}
+	functions["progress"] = queryFunc{
+		fn:               progress,
+		argTypes:         []DType{QItem},
+		resultType:       QInt,
+		trailingOptional: false,
+		injectIt:         true,
+		wantsContext:     true,
+	}
+	return nil
+}
+
+func parent(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	if p := ParentOf(list, item); p != nil {
+		return []any{p}
+	}
+	return []any{}
+}
+
+func children(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	return toAnySlice(ChildrenOf(list, item))
+}
+
+func progress(args []any) any {
+	list := args[0].(map[string]any)["_list"].(*todotxt.List)
+	item := args[1].(*todotxt.Item)
+	return ProgressOf(list, item)
+}
+
+// ParentOf returns the parent of item or nil if it has none.
+// Ids of tasks that are not part of the list are ignored.
+func ParentOf(list *todotxt.List, item *todotxt.Item) *todotxt.Item {
+	if subtaskTag == "" {
+		return nil
+	}
+	values := item.TagValues(subtaskTag)
+	if len(values) == 0 {
+		return nil
+	}
+	if p := subtaskIndexOf(list).byId[values[0]]; p != item {
+		return p
+	}
+	return nil
+}
+
+// ChildrenOf returns the direct subtasks of item.
+func ChildrenOf(list *todotxt.List, item *todotxt.Item) []*todotxt.Item {
+	if subtaskTag == "" || idOf(item) == "" {
+		return nil
+	}
+	return subtaskIndexOf(list).children[idOf(item)]
+}
+
+// ProgressOf returns the percentage of completed subtasks of item, including the subtasks of subtasks.
+// For a task without subtasks it is 100 if the task is done and 0 otherwise.
+func ProgressOf(list *todotxt.List, item *todotxt.Item) int {
+	done, total := 0, 0
+	visited := map[*todotxt.Item]struct{}{item: {}}
+	queue := ChildrenOf(list, item)
+	for len(queue) > 0 {
+		child := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[child]; ok {
+			continue
+		}
+		visited[child] = struct{}{}
+		total++
+		if child.Done() {
+			done++
+		}
+		queue = append(queue, ChildrenOf(list, child)...)
+	}
+	switch {
+	case total > 0:
+		return done * 100 / total
+	case item.Done():
+		return 100
+	}
+	return 0
+}
+
+type subtaskIndex struct {
+	byId     map[string]*todotxt.Item
+	children map[string][]*todotxt.Item
+}
+
+// subtaskIndexCache remembers the index of the last list, so that parent(it) is not O(n) for every item.
+var subtaskIndexCache struct {
+	mu      sync.Mutex
+	list    *todotxt.List
+	version uint64
+	index   subtaskIndex
+}
+
+func subtaskIndexOf(list *todotxt.List) subtaskIndex {
+	subtaskIndexCache.mu.Lock()
+	defer subtaskIndexCache.mu.Unlock()
+	if subtaskIndexCache.list == list && subtaskIndexCache.version == list.Version() {
+		return subtaskIndexCache.index
+	}
+	index := subtaskIndex{
+		byId:     make(map[string]*todotxt.Item),
+		children: make(map[string][]*todotxt.Item),
+	}
+	for _, t := range list.Tasks() {
+		if id := idOf(t); id != "" {
+			if _, ok := index.byId[id]; !ok {
+				index.byId[id] = t
+			}
+		}
+		if values := t.TagValues(subtaskTag); len(values) > 0 && values[0] != idOf(t) {
+			index.children[values[0]] = append(index.children[values[0]], t)
+		}
+	}
+	subtaskIndexCache.list, subtaskIndexCache.version, subtaskIndexCache.index = list, list.Version(), index
+	return index
+}
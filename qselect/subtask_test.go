@@ -0,0 +1,63 @@
+package qselect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SubtaskFunctions(t *testing.T) {
+	RegisterIdTag("id")
+	assert.Nil(t, RegisterSubtaskTag("parent"))
+	defer RegisterIdTag("")
+	defer RegisterSubtaskTag("")
+	list := listFromString(t, `
+	release id:a
+	x 2022-01-02 2022-01-01 write code id:b parent:a
+	write tests id:c parent:a
+	write unit tests id:d parent:c
+	x 2022-01-02 2022-01-01 write integration tests id:e parent:c
+	`)
+	testCases := map[string]struct {
+		query           string
+		expectedMatches []string
+	}{
+		"parent": {
+			query:           `exists p in parent(it): tag(p, "id") == "c"`,
+			expectedMatches: []string{"write unit tests id:d parent:c", "x 2022-01-02 2022-01-01 write integration tests id:e parent:c"},
+		},
+		"top level tasks": {
+			query:           `forall p in parent(it): false`,
+			expectedMatches: []string{"release id:a"},
+		},
+		"children": {
+			query:           `exists c in children(it): done(c)`,
+			expectedMatches: []string{"release id:a", "write tests id:c parent:a"},
+		},
+		"progress includes the subtasks of subtasks": {
+			query:           `progress(it) == 50`,
+			expectedMatches: []string{"release id:a", "write tests id:c parent:a"},
+		},
+		"progress of tasks without subtasks": {
+			query:           `progress(it) == 100`,
+			expectedMatches: []string{"x 2022-01-02 2022-01-01 write code id:b parent:a", "x 2022-01-02 2022-01-01 write integration tests id:e parent:c"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			query, err := CompileQQL(tc.query)
+			assert.Nil(t, err)
+			matches := query.Filter(list)
+			lines := make([]string, 0, len(matches))
+			for _, m := range matches {
+				lines = append(lines, m.String())
+			}
+			assert.ElementsMatch(t, tc.expectedMatches, lines)
+		})
+	}
+}
+
+func Test_SubtaskTagRequiresIds(t *testing.T) {
+	assert.ErrorIs(t, RegisterSubtaskTag("parent"), ErrSubtasksWithoutIds)
+}
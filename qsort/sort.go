@@ -47,6 +47,10 @@ func (c Compiler) CompileSortFunc(sortingKeys []string) (func(*todotxt.Item, *to
 		case "score":
 			compareFuncs = append(compareFuncs, order.compareScores(c.ScoreCalculator))
 		default:
+			if fuzzyQuery, ok := strings.CutPrefix(key, "fuzzy:"); ok {
+				compareFuncs = append(compareFuncs, order.compareFuzzy(fuzzyQuery))
+				continue
+			}
 			if !strings.HasPrefix(key, "tag:") {
 				return nil, fmt.Errorf("unknown sort key %s", key)
 			}
@@ -107,8 +111,8 @@ func (o sortOrder) compareCompletion(i1 *todotxt.Item, i2 *todotxt.Item) int {
 
 func (o sortOrder) compareTag(tagKey string, tagTypes map[string]qselect.DType) func(*todotxt.Item, *todotxt.Item) int {
 	return func(i1, i2 *todotxt.Item) int {
-		firstTags := i1.Tags()[tagKey]
-		secondTags := i2.Tags()[tagKey]
+		firstTags := i1.TagValues(tagKey)
+		secondTags := i2.TagValues(tagKey)
 		switch {
 		case len(firstTags) == 0 && len(secondTags) == 0:
 			return 0
@@ -128,7 +132,7 @@ func (o sortOrder) compareTag(tagKey string, tagTypes map[string]qselect.DType)
 					valueOrNil(qduration.Parse(firstTags[0])),
 					valueOrNil(qduration.Parse(secondTags[0])),
 					func(d1, d2 qduration.Duration) int {
-						return cmp.Compare(d1.Days(), d2.Days())
+						return cmp.Compare(d1.Minutes(), d2.Minutes())
 					},
 				)
 			case qselect.QDate:
@@ -139,6 +143,14 @@ func (o sortOrder) compareTag(tagKey string, tagTypes map[string]qselect.DType)
 						return t1.Compare(t2)
 					},
 				)
+			case qselect.QDateTime:
+				return int(o) * compareOptionalsFunc(
+					valueOrNil(time.Parse(qselect.DateTimeLayout, firstTags[0])),
+					valueOrNil(time.Parse(qselect.DateTimeLayout, secondTags[0])),
+					func(t1, t2 time.Time) int {
+						return t1.Compare(t2)
+					},
+				)
 			default:
 				return int(o) * strings.Compare(firstTags[0], secondTags[0])
 			}
@@ -158,6 +170,19 @@ func (o sortOrder) compareScores(calc qscore.Calculator) func(*todotxt.Item, *to
 	}
 }
 
+func (o sortOrder) compareFuzzy(query string) func(*todotxt.Item, *todotxt.Item) int {
+	return func(i1, i2 *todotxt.Item) int {
+		return int(o) * compareOptionals(fuzzyScore(query, i1), fuzzyScore(query, i2))
+	}
+}
+
+func fuzzyScore(query string, i *todotxt.Item) *int {
+	if score, ok := qselect.FuzzyScoreOf(query, i); ok {
+		return &score
+	}
+	return nil
+}
+
 func (o sortOrder) compareProject(i1 *todotxt.Item, i2 *todotxt.Item) int {
 	i1Projects := i1.Projects()
 	i2Projects := i2.Projects()
@@ -155,6 +155,18 @@ func Test_Sort(t *testing.T) {
 			second:        todotxt.MustBuildItem(todotxt.WithDone(false), todotxt.WithDescription("@b @c T1")),
 			expectedOrder: secondSmaller,
 		},
+		"better fuzzy matches are greater": {
+			sortString:    "+fuzzy:wrtrpt",
+			first:         todotxt.MustBuildItem(todotxt.WithDescription("write report")),
+			second:        todotxt.MustBuildItem(todotxt.WithDescription("wait for the reporting tool")),
+			expectedOrder: secondSmaller,
+		},
+		"fuzzy non matches are smallest": {
+			sortString:    "-fuzzy:wrtrpt",
+			first:         todotxt.MustBuildItem(todotxt.WithDescription("something else")),
+			second:        todotxt.MustBuildItem(todotxt.WithDescription("write report")),
+			expectedOrder: secondSmaller,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -0,0 +1,235 @@
+package qstats
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+type Period int
+
+const (
+	Day Period = iota
+	Week
+	Month
+)
+
+var periodMap = map[string]Period{
+	"day":   Day,
+	"week":  Week,
+	"month": Month,
+}
+
+func ParsePeriod(period string) (Period, error) {
+	p, ok := periodMap[strings.ToLower(period)]
+	if !ok {
+		return 0, fmt.Errorf("unknown period %s. Must be one of day, week or month", period)
+	}
+	return p, nil
+}
+
+// Start returns the beginning of the period that contains t (as a UTC date).
+// Weeks start on monday.
+func (p Period) Start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case Day:
+		return day
+	case Week:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		panic("unknown period")
+	}
+}
+
+// Next returns the beginning of the period that follows the one starting at start.
+func (p Period) Next(start time.Time) time.Time {
+	switch p {
+	case Day:
+		return start.AddDate(0, 0, 1)
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	default:
+		panic("unknown period")
+	}
+}
+
+func (p Period) Format(start time.Time) string {
+	switch p {
+	case Week:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case Month:
+		return start.Format("2006-01")
+	default:
+		return start.Format(time.DateOnly)
+	}
+}
+
+// Bucket counts the tasks that were created and completed within a single period.
+type Bucket struct {
+	Start     time.Time `json:"start"`
+	Label     string    `json:"label"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+// Group summarizes all tasks that share a project, context or tag value.
+type Group struct {
+	Key          string  `json:"key"`
+	Open         int     `json:"open"`
+	Done         int     `json:"done"`
+	LeadTimeDays float64 `json:"lead-time-days"`
+	leadTimeSum  float64
+	leadTimes    int
+}
+
+type Report struct {
+	Total        int                `json:"total"`
+	Open         int                `json:"open"`
+	Done         int                `json:"done"`
+	LeadTimeDays float64            `json:"lead-time-days"`
+	Timeline     []Bucket           `json:"timeline"`
+	Projects     []Group            `json:"projects"`
+	Contexts     []Group            `json:"contexts"`
+	Tags         map[string][]Group `json:"tags"`
+}
+
+// Calculator aggregates statistics over a set of tasks.
+// The timeline covers all periods from Begin until NowFunc().
+type Calculator struct {
+	Period  Period
+	Begin   time.Time
+	Tags    []string
+	NowFunc func() time.Time
+}
+
+func (c Calculator) Report(items []*todotxt.Item) Report {
+	report := Report{
+		Total:    len(items),
+		Timeline: c.timeline(items),
+		Tags:     make(map[string][]Group),
+	}
+	overall := Group{}
+	for _, i := range items {
+		overall.add(i)
+	}
+	report.Open, report.Done, report.LeadTimeDays = overall.Open, overall.Done, overall.average()
+	report.Projects = groupBy(items, Projects)
+	report.Contexts = groupBy(items, Contexts)
+	for _, tag := range c.Tags {
+		report.Tags[tag] = groupBy(items, func(i *todotxt.Item) []string {
+			return i.Tags()[tag]
+		})
+	}
+	return report
+}
+
+func (c Calculator) timeline(items []*todotxt.Item) []Bucket {
+	now := c.Period.Start(c.now())
+	buckets := make([]Bucket, 0)
+	index := make(map[time.Time]int)
+	for start := c.Period.Start(c.Begin); !start.After(now); start = c.Period.Next(start) {
+		index[start] = len(buckets)
+		buckets = append(buckets, Bucket{Start: start, Label: c.Period.Format(start)})
+	}
+	for _, i := range items {
+		if creation := i.CreationDate(); creation != nil {
+			if idx, ok := index[c.Period.Start(*creation)]; ok {
+				buckets[idx].Created++
+			}
+		}
+		if completion := i.CompletionDate(); i.Done() && completion != nil {
+			if idx, ok := index[c.Period.Start(*completion)]; ok {
+				buckets[idx].Completed++
+			}
+		}
+	}
+	return buckets
+}
+
+func (c Calculator) now() time.Time {
+	if c.NowFunc != nil {
+		return c.NowFunc()
+	}
+	return time.Now()
+}
+
+func (g *Group) add(i *todotxt.Item) {
+	if !i.Done() {
+		g.Open++
+		return
+	}
+	g.Done++
+	if lead, ok := LeadTime(i); ok {
+		g.leadTimeSum += lead.Hours() / 24
+		g.leadTimes++
+	}
+}
+
+func (g *Group) average() float64 {
+	if g.leadTimes == 0 {
+		return 0
+	}
+	return g.leadTimeSum / float64(g.leadTimes)
+}
+
+// LeadTime returns the time between creation and completion of a done task.
+func LeadTime(i *todotxt.Item) (time.Duration, bool) {
+	creation, completion := i.CreationDate(), i.CompletionDate()
+	if !i.Done() || creation == nil || completion == nil {
+		return 0, false
+	}
+	return completion.Sub(*creation), true
+}
+
+// groupBy groups the items by the keys returned from keys. Items with multiple keys
+// are counted for every key. The result is ordered by the number of tasks (descending).
+func groupBy(items []*todotxt.Item, keys func(*todotxt.Item) []string) []Group {
+	groups := make(map[string]*Group)
+	for _, i := range items {
+		for _, k := range keys(i) {
+			if _, ok := groups[k]; !ok {
+				groups[k] = &Group{Key: k}
+			}
+			groups[k].add(i)
+		}
+	}
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		g.LeadTimeDays = g.average()
+		result = append(result, *g)
+	}
+	slices.SortFunc(result, func(a, b Group) int {
+		if a.Open+a.Done != b.Open+b.Done {
+			return (b.Open + b.Done) - (a.Open + a.Done)
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// Projects returns the projects of i as strings. It can be used as a key function for grouping.
+func Projects(i *todotxt.Item) []string {
+	return toStrings(i.Projects())
+}
+
+// Contexts returns the contexts of i as strings. It can be used as a key function for grouping.
+func Contexts(i *todotxt.Item) []string {
+	return toStrings(i.Contexts())
+}
+
+func toStrings[T fmt.Stringer](values []T) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		result = append(result, v.String())
+	}
+	return result
+}
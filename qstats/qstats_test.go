@@ -0,0 +1,64 @@
+package qstats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+// Wednesday
+var today = time.Date(2022, 2, 2, 0, 0, 0, 0, time.UTC)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2022, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func Test_PeriodStart(t *testing.T) {
+	assert.Equal(t, date(2, 2), qstats.Day.Start(today))
+	assert.Equal(t, date(1, 31), qstats.Week.Start(today))
+	assert.Equal(t, date(2, 1), qstats.Month.Start(today))
+	assert.Equal(t, date(1, 31), qstats.Week.Start(date(1, 31)))
+	assert.Equal(t, date(1, 31), qstats.Week.Start(date(2, 6)))
+}
+
+func Test_Report(t *testing.T) {
+	items := []*todotxt.Item{
+		todotxt.MustBuildItem(todotxt.WithDescription("open +a @home"), todotxt.WithCreationDate(date(1, 20))),
+		todotxt.MustBuildItem(todotxt.WithDescription("done +a due:x"), todotxt.WithDone(true), todotxt.WithCreationDate(date(1, 25)), todotxt.WithCompletionDate(date(1, 27))),
+		todotxt.MustBuildItem(todotxt.WithDescription("done +b due:x"), todotxt.WithDone(true), todotxt.WithCreationDate(date(1, 31)), todotxt.WithCompletionDate(date(2, 2))),
+		todotxt.MustBuildItem(todotxt.WithDescription("done without dates"), todotxt.WithDone(true), todotxt.WithoutCompletionDate(), todotxt.WithoutCreationDate()),
+	}
+	calc := qstats.Calculator{
+		Period:  qstats.Week,
+		Begin:   date(1, 20),
+		Tags:    []string{"due"},
+		NowFunc: func() time.Time { return today },
+	}
+
+	report := calc.Report(items)
+
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 1, report.Open)
+	assert.Equal(t, 3, report.Done)
+	assert.InDelta(t, 2.0, report.LeadTimeDays, 0.001)
+
+	assert.Equal(t, []qstats.Bucket{
+		{Start: date(1, 17), Label: "2022-W03", Created: 1, Completed: 0},
+		{Start: date(1, 24), Label: "2022-W04", Created: 1, Completed: 1},
+		{Start: date(1, 31), Label: "2022-W05", Created: 1, Completed: 1},
+	}, report.Timeline)
+
+	assert.Len(t, report.Projects, 2)
+	assert.Equal(t, "+a", report.Projects[0].Key)
+	assert.Equal(t, 1, report.Projects[0].Open)
+	assert.Equal(t, 1, report.Projects[0].Done)
+	assert.Equal(t, "+b", report.Projects[1].Key)
+	assert.Len(t, report.Contexts, 1)
+	assert.Equal(t, "@home", report.Contexts[0].Key)
+	assert.Len(t, report.Tags["due"], 1)
+	assert.Equal(t, 2, report.Tags["due"][0].Done)
+	assert.InDelta(t, 2.0, report.Tags["due"][0].LeadTimeDays, 0.001)
+}
@@ -0,0 +1,87 @@
+package qstats
+
+import (
+	"time"
+
+	"github.com/Fabian-G/quest/todotxt"
+)
+
+// Series is a sequence of values, one for every period of the timeline.
+type Series struct {
+	Label  string   `json:"label"`
+	Starts []string `json:"starts"`
+	Values []int    `json:"values"`
+}
+
+// OpenSeries returns the number of open tasks at the end of every period.
+// Tasks without creation date are considered to have been open since ever.
+func (c Calculator) OpenSeries(label string, items []*todotxt.Item) Series {
+	series := Series{Label: label}
+	now := c.Period.Start(c.now())
+	for start := c.Period.Start(c.Begin); !start.After(now); start = c.Period.Next(start) {
+		series.Starts = append(series.Starts, c.Period.Format(start))
+		end := c.Period.Next(start)
+		open := 0
+		for _, i := range items {
+			if isOpenAt(i, end) {
+				open++
+			}
+		}
+		series.Values = append(series.Values, open)
+	}
+	return series
+}
+
+// OpenSeriesBy returns one OpenSeries for every key (e.g. project) returned by keys.
+// The series are ordered like the groups of a Report.
+func (c Calculator) OpenSeriesBy(items []*todotxt.Item, keys func(*todotxt.Item) []string) []Series {
+	groups := groupBy(items, keys)
+	series := make([]Series, 0, len(groups))
+	for _, g := range groups {
+		member := make([]*todotxt.Item, 0)
+		for _, i := range items {
+			for _, k := range keys(i) {
+				if k == g.Key {
+					member = append(member, i)
+					break
+				}
+			}
+		}
+		series = append(series, c.OpenSeries(g.Key, member))
+	}
+	return series
+}
+
+// isOpenAt reports whether the task was open right before t
+func isOpenAt(i *todotxt.Item, t time.Time) bool {
+	if creation := i.CreationDate(); creation != nil && !creation.Before(t) {
+		return false
+	}
+	if !i.Done() {
+		return true
+	}
+	completion := i.CompletionDate()
+	return completion != nil && !completion.Before(t)
+}
+
+// CompletionsPerWeekday counts the completed tasks for each weekday (starting with monday).
+func CompletionsPerWeekday(items []*todotxt.Item) [7]int {
+	var result [7]int
+	for _, i := range items {
+		if completion := i.CompletionDate(); i.Done() && completion != nil {
+			result[(int(completion.Weekday())+6)%7]++
+		}
+	}
+	return result
+}
+
+// CompletionsPerDay counts the completed tasks per day. The keys are UTC dates.
+func CompletionsPerDay(items []*todotxt.Item) map[time.Time]int {
+	result := make(map[time.Time]int)
+	for _, i := range items {
+		if completion := i.CompletionDate(); i.Done() && completion != nil {
+			result[Day.Start(*completion)]++
+		}
+	}
+	return result
+}
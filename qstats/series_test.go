@@ -0,0 +1,46 @@
+package qstats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OpenSeries(t *testing.T) {
+	items := []*todotxt.Item{
+		todotxt.MustBuildItem(todotxt.WithDescription("open +a"), todotxt.WithCreationDate(date(1, 31))),
+		todotxt.MustBuildItem(todotxt.WithDescription("done +a"), todotxt.WithDone(true), todotxt.WithCreationDate(date(1, 30)), todotxt.WithCompletionDate(date(2, 1))),
+		todotxt.MustBuildItem(todotxt.WithDescription("no creation +b"), todotxt.WithoutCreationDate()),
+	}
+	calc := qstats.Calculator{
+		Period:  qstats.Day,
+		Begin:   date(1, 29),
+		NowFunc: func() time.Time { return today },
+	}
+
+	series := calc.OpenSeries("open", items)
+	assert.Equal(t, []string{"2022-01-29", "2022-01-30", "2022-01-31", "2022-02-01", "2022-02-02"}, series.Starts)
+	assert.Equal(t, []int{1, 2, 3, 2, 2}, series.Values)
+
+	byProject := calc.OpenSeriesBy(items, qstats.Projects)
+	assert.Len(t, byProject, 2)
+	assert.Equal(t, "+a", byProject[0].Label)
+	assert.Equal(t, []int{0, 1, 2, 1, 1}, byProject[0].Values)
+	assert.Equal(t, "+b", byProject[1].Label)
+	assert.Equal(t, []int{1, 1, 1, 1, 1}, byProject[1].Values)
+}
+
+func Test_Completions(t *testing.T) {
+	items := []*todotxt.Item{
+		todotxt.MustBuildItem(todotxt.WithDescription("a"), todotxt.WithDone(true), todotxt.WithCreationDate(date(1, 1)), todotxt.WithCompletionDate(date(1, 31))),
+		todotxt.MustBuildItem(todotxt.WithDescription("b"), todotxt.WithDone(true), todotxt.WithCreationDate(date(1, 1)), todotxt.WithCompletionDate(date(1, 31))),
+		todotxt.MustBuildItem(todotxt.WithDescription("c"), todotxt.WithDone(true), todotxt.WithCreationDate(date(1, 1)), todotxt.WithCompletionDate(date(2, 6))),
+		todotxt.MustBuildItem(todotxt.WithDescription("open"), todotxt.WithCreationDate(date(1, 1))),
+	}
+
+	assert.Equal(t, [7]int{2, 0, 0, 0, 0, 0, 1}, qstats.CompletionsPerWeekday(items))
+	assert.Equal(t, map[time.Time]int{date(1, 31): 2, date(2, 6): 1}, qstats.CompletionsPerDay(items))
+}
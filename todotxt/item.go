@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,9 +26,19 @@ type Item struct {
 	completionDate *time.Time
 	creationDate   *time.Time
 	description    string
+	parsed         *parsedDescription
 	emitFunc       func(Event) error
 }
 
+// parsedDescription memoizes the projects, contexts and tags of a description.
+// It is replaced whenever the description changes, so the memoized values are never outdated.
+type parsedDescription struct {
+	once     sync.Once
+	projects []Project
+	contexts []Context
+	tags     Tags
+}
+
 func (i *Item) Done() bool {
 	return i.done
 }
@@ -73,37 +84,62 @@ func (i *Item) CleanDescription(projects []Project, contexts []Context, tags []s
 }
 
 func (i *Item) Projects() []Project {
-	matches := i.findDescMatches(projectRegex)
-	projects := make([]Project, 0, len(matches))
-	for _, match := range matches {
-		projects = append(projects, Project(strings.TrimSpace(match)[1:]))
-	}
-	slices.Sort(projects)
-	projects = slices.Compact(projects)
-	return projects
+	return slices.Clone(i.parse().projects)
 }
 
 func (i *Item) Contexts() []Context {
-	matches := i.findDescMatches(contextRegex)
-	contexts := make([]Context, 0, len(matches))
-	for _, match := range matches {
-		contexts = append(contexts, Context(strings.TrimSpace(match)[1:]))
-	}
-	slices.Sort(contexts)
-	contexts = slices.Compact(contexts)
-	return contexts
+	return slices.Clone(i.parse().contexts)
 }
 
 func (i *Item) Tags() Tags {
-	matches := i.findDescMatches(tagRegex)
-	tags := make(Tags)
-	for _, match := range matches {
+	tags := make(Tags, len(i.parse().tags))
+	for k, v := range i.parse().tags {
+		tags[k] = slices.Clone(v)
+	}
+	return tags
+}
+
+// TagValues returns the values of the tag key. It is cheaper than Tags()[key], because only the values of key are copied.
+func (i *Item) TagValues(key string) []string {
+	return slices.Clone(i.parse().tags[key])
+}
+
+// parse returns the (memoized) projects, contexts and tags of the description.
+func (i *Item) parse() *parsedDescription {
+	if i.parsed == nil {
+		// Items that have not been modified through modify (e.g. the zero value) are not memoized
+		p := &parsedDescription{}
+		p.parseFrom(i)
+		return p
+	}
+	i.parsed.once.Do(func() { i.parsed.parseFrom(i) })
+	return i.parsed
+}
+
+func (p *parsedDescription) parseFrom(i *Item) {
+	projectMatches := i.findDescMatches(projectRegex)
+	p.projects = make([]Project, 0, len(projectMatches))
+	for _, match := range projectMatches {
+		p.projects = append(p.projects, Project(strings.TrimSpace(match)[1:]))
+	}
+	slices.Sort(p.projects)
+	p.projects = slices.Compact(p.projects)
+
+	contextMatches := i.findDescMatches(contextRegex)
+	p.contexts = make([]Context, 0, len(contextMatches))
+	for _, match := range contextMatches {
+		p.contexts = append(p.contexts, Context(strings.TrimSpace(match)[1:]))
+	}
+	slices.Sort(p.contexts)
+	p.contexts = slices.Compact(p.contexts)
+
+	p.tags = make(Tags)
+	for _, match := range i.findDescMatches(tagRegex) {
 		tagSepIndex := strings.Index(match, ":")
 		key := strings.TrimSpace(match[:tagSepIndex])
 		value := strings.TrimSpace(match[tagSepIndex+1:])
-		tags[key] = append(tags[key], value)
+		p.tags[key] = append(p.tags[key], value)
 	}
-	return tags
 }
 
 func (i *Item) findDescMatches(regex *regexp.Regexp) []string {
@@ -222,6 +258,9 @@ func (i *Item) String() string {
 func (i *Item) modify(modification func()) error {
 	previous := *i
 	modification()
+	if i.parsed == nil || i.description != previous.description {
+		i.parsed = &parsedDescription{}
+	}
 	err := i.emit(ModEvent{
 		Previous: &previous,
 		Current:  i,
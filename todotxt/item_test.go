@@ -246,6 +246,27 @@ func Test_ClearTag(t *testing.T) {
 	assert.Equal(t, "This is a description with tags", item.Description())
 }
 
+func Test_TagsAreUpdatedAfterModification(t *testing.T) {
+	item := todotxt.MustBuildItem(todotxt.WithDescription("a task +foo @home rec:5y"))
+	assert.Equal(t, todotxt.Tags{"rec": {"5y"}}, item.Tags())
+
+	assert.Nil(t, item.SetTag("rec", "1d"))
+	assert.Nil(t, item.EditDescription(item.Description()+" +bar"))
+
+	assert.Equal(t, todotxt.Tags{"rec": {"1d"}}, item.Tags())
+	assert.Equal(t, []todotxt.Project{"bar", "foo"}, item.Projects())
+}
+
+func Test_ModifyingReturnedTagsDoesNotAffectTheItem(t *testing.T) {
+	item := todotxt.MustBuildItem(todotxt.WithDescription("a task +foo rec:5y"))
+
+	item.Tags()["rec"][0] = "1d"
+	item.Projects()[0] = "bar"
+
+	assert.Equal(t, todotxt.Tags{"rec": {"5y"}}, item.Tags())
+	assert.Equal(t, []todotxt.Project{"foo"}, item.Projects())
+}
+
 func Test_CleaDescripton(t *testing.T) {
 	testCases := map[string]struct {
 		cleanProjects      []todotxt.Project
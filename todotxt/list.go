@@ -31,7 +31,11 @@ type List struct {
 	deletionsStore map[int]struct{}
 	items          []*Item // The items (event the deleted items)
 	hooksDisabled  bool
+	dispatching    bool
+	afterHooks     []func() error
 	hooks          []Hook
+	sources        map[*Item]*Repo
+	version        uint64
 }
 
 func ListOf(items ...*Item) *List {
@@ -51,6 +55,12 @@ func (l *List) Tasks() []*Item {
 	return tasks
 }
 
+// Version is increased on every change of the list (including changes of its items).
+// It can be used to detect whether data derived from the list is outdated.
+func (l *List) Version() uint64 {
+	return l.version
+}
+
 func (l *List) Add(items ...*Item) (err error) {
 	originalLength := len(l.items)
 	defer func() {
@@ -99,6 +109,23 @@ func (l *List) LineOf(i *Item) int {
 	return slices.Index(l.items, i) + 1
 }
 
+// SourceOf returns the repo the item was read from.
+// The result is nil if the item has not been read from a repo (e.g. because it has just been added).
+func (l *List) SourceOf(i *Item) *Repo {
+	return l.sources[i]
+}
+
+// SetSource sets the repo the items belong to. When the list is saved through a Union,
+// the items are written to that repo.
+func (l *List) SetSource(repo *Repo, items ...*Item) {
+	if l.sources == nil {
+		l.sources = make(map[*Item]*Repo)
+	}
+	for _, i := range items {
+		l.sources[i] = repo
+	}
+}
+
 func (l *List) Len() int {
 	return len(l.Tasks())
 }
@@ -179,6 +206,7 @@ func (l *List) Reset() {
 		*l.items[i] = item
 	}
 	l.items = l.items[:len(l.snapshot.items)]
+	l.version++
 }
 
 func (l *List) deletions() map[int]struct{} {
@@ -204,13 +232,29 @@ func (l *List) validate() error {
 }
 
 func (l *List) emit(me Event) error {
+	l.version++
 	if l.hooksDisabled {
 		return nil
 	}
 
-	l.hooksDisabled = true
+	err := l.dispatch(me)
+	afterHooks := l.afterHooks
+	l.afterHooks = nil
+	if err != nil {
+		return err
+	}
+	for _, change := range afterHooks {
+		if err := change(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *List) dispatch(me Event) error {
+	l.hooksDisabled, l.dispatching = true, true
 	defer func() {
-		l.hooksDisabled = false
+		l.hooksDisabled, l.dispatching = false, false
 	}()
 	for _, h := range l.hooks {
 		if err := me.Dispatch(l, h); err != nil {
@@ -219,3 +263,14 @@ func (l *List) emit(me Event) error {
 	}
 	return nil
 }
+
+// AfterHooks runs change once the hooks of the current modification have finished.
+// Unlike a change made directly within a hook, it triggers the hooks itself.
+// Outside of a hook change runs immediately.
+func (l *List) AfterHooks(change func() error) error {
+	if !l.dispatching {
+		return change()
+	}
+	l.afterHooks = append(l.afterHooks, change)
+	return nil
+}
@@ -135,6 +135,29 @@ func Test_ModificationsMadeInTheHookGetThroughToTheList(t *testing.T) {
 	assert.False(t, list.GetLine(1).Done())
 }
 
+func Test_ChangesAfterTheHooksTriggerTheHooksThemselves(t *testing.T) {
+	list := todotxt.ListOf(
+		todotxt.MustBuildItem(todotxt.WithDescription("first")),
+		todotxt.MustBuildItem(todotxt.WithDescription("second")),
+	)
+	completed := make([]string, 0)
+	list.AddHook(todotxt.HookFunc(func(list *todotxt.List, event todotxt.ModEvent) error {
+		if !event.IsCompleteEvent() {
+			return nil
+		}
+		completed = append(completed, event.Current.Description())
+		if event.Current == list.GetLine(1) {
+			return list.AfterHooks(list.GetLine(2).Complete)
+		}
+		return nil
+	}))
+
+	assert.Nil(t, list.GetLine(1).Complete())
+
+	assert.True(t, list.GetLine(2).Done())
+	assert.Equal(t, []string{"first", "second"}, completed)
+}
+
 func TestList_Remove(t *testing.T) {
 	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("Hello World")))
 
@@ -181,3 +204,22 @@ func Test_SnapshotAndRestore(t *testing.T) {
 	assert.Equal(t, "B", list.GetLine(2).Description())
 
 }
+
+func Test_VersionChangesOnModification(t *testing.T) {
+	list := todotxt.ListOf(todotxt.MustBuildItem(todotxt.WithDescription("A")))
+	list.Snapshot()
+	versions := []uint64{list.Version()}
+
+	assert.Nil(t, list.GetLine(1).EditDescription("B"))
+	versions = append(versions, list.Version())
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("C"))))
+	versions = append(versions, list.Version())
+	assert.Nil(t, list.Remove(2))
+	versions = append(versions, list.Version())
+	list.Reset()
+	versions = append(versions, list.Version())
+
+	for i := 1; i < len(versions); i++ {
+		assert.Greater(t, versions[i], versions[i-1])
+	}
+}
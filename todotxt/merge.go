@@ -0,0 +1,205 @@
+package todotxt
+
+// merge3 performs a line based three-way merge of the task lists ours and theirs,
+// which have both been derived from base.
+// The second return value is false if both sides changed the same task in different ways.
+// Tasks that have been added by both sides at the same location are not considered a
+// conflict. In that case our tasks are placed before theirs.
+func merge3(base, ours, theirs []*Item) ([]*Item, bool) {
+	oursMatch := matchItems(base, ours)
+	theirsMatch := matchItems(base, theirs)
+
+	merged := make([]*Item, 0, max(len(ours), len(theirs)))
+	b, o, t := 0, 0, 0
+	for {
+		// find the next base task that is unchanged on both sides
+		syncB := b
+		for syncB < len(base) && (oursMatch[syncB] == -1 || theirsMatch[syncB] == -1) {
+			syncB++
+		}
+		syncO, syncT := len(ours), len(theirs)
+		if syncB < len(base) {
+			syncO, syncT = oursMatch[syncB], theirsMatch[syncB]
+		}
+		chunk, ok := mergeChunk(base[b:syncB], ours[o:syncO], theirs[t:syncT], relative(oursMatch[b:syncB], o), relative(theirsMatch[b:syncB], t))
+		if !ok {
+			return nil, false
+		}
+		merged = append(merged, chunk...)
+		if syncB == len(base) {
+			return merged, true
+		}
+		merged = append(merged, ours[syncO])
+		b, o, t = syncB+1, syncO+1, syncT+1
+	}
+}
+
+func mergeChunk(base, ours, theirs []*Item, oursMatch, theirsMatch []int) ([]*Item, bool) {
+	switch {
+	case itemsEqual(base, ours):
+		return theirs, true
+	case itemsEqual(base, theirs):
+		return ours, true
+	case itemsEqual(ours, theirs):
+		return ours, true
+	}
+	for i := range base {
+		if oursMatch[i] == -1 && theirsMatch[i] == -1 {
+			return nil, false // both sides changed the same task
+		}
+	}
+	// Each task was changed by at most one side, so we can apply both changes.
+	// Added tasks stay in front of the base task that follows them.
+	oursAnchors := anchors(ours, oursMatch)
+	theirsAnchors := anchors(theirs, theirsMatch)
+	merged := make([]*Item, 0, len(ours)+len(theirs))
+	for i := 0; i <= len(base); i++ {
+		merged = append(merged, oursAnchors[i]...)
+		merged = append(merged, theirsAnchors[i]...)
+		if i < len(base) && oursMatch[i] != -1 && theirsMatch[i] != -1 {
+			merged = append(merged, base[i])
+		}
+	}
+	return merged, true
+}
+
+// anchors groups the tasks of side that have no match in base
+// by the index of the next base task that is present in side.
+func anchors(side []*Item, match []int) [][]*Item {
+	result := make([][]*Item, len(match)+1)
+	s := 0
+	for i := 0; i <= len(match); i++ {
+		next := len(side)
+		if i < len(match) {
+			if match[i] == -1 {
+				continue
+			}
+			next = match[i]
+		}
+		result[i] = side[s:next]
+		s = next + 1
+	}
+	return result
+}
+
+func relative(match []int, offset int) []int {
+	result := make([]int, len(match))
+	for i, m := range match {
+		result[i] = m
+		if m != -1 {
+			result[i] = m - offset
+		}
+	}
+	return result
+}
+
+func itemsEqual(a, b []*Item) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchItems computes the longest common subsequence of base and other.
+// The result maps every base index to the matching index in other or -1.
+func matchItems(base, other []*Item) []int {
+	match := make([]int, len(base))
+	prefix := 0
+	for prefix < len(base) && prefix < len(other) && base[prefix].Equals(other[prefix]) {
+		match[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(base)-prefix && suffix < len(other)-prefix && base[len(base)-1-suffix].Equals(other[len(other)-1-suffix]) {
+		match[len(base)-1-suffix] = len(other) - 1 - suffix
+		suffix++
+	}
+	b := base[prefix : len(base)-suffix]
+	o := other[prefix : len(other)-suffix]
+
+	lcs := make([][]int, len(b)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(o)+1)
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		for j := len(o) - 1; j >= 0; j-- {
+			if b[i].Equals(o[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(b) {
+		switch {
+		case j < len(o) && b[i].Equals(o[j]):
+			match[prefix+i] = prefix + j
+			i++
+			j++
+		case j < len(o) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+		default:
+			match[prefix+i] = -1
+			i++
+		}
+	}
+	return match
+}
+
+type ChangeKind int
+
+const (
+	Unchanged ChangeKind = iota
+	Added
+	Removed
+	Modified
+)
+
+// TaskChange describes how a single task differs between two versions of a list.
+// Ours is nil for added tasks and Theirs is nil for removed tasks.
+type TaskChange struct {
+	Kind   ChangeKind
+	Ours   *Item
+	Theirs *Item
+}
+
+// DiffTasks compares the tasks of ours and theirs and returns the changes
+// in the order of the combined list. Tasks that only differ in one version are paired
+// up as modifications if they are located at the same position.
+func DiffTasks(ours, theirs []*Item) []TaskChange {
+	match := matchItems(ours, theirs)
+	changes := make([]TaskChange, 0, max(len(ours), len(theirs)))
+	o, t := 0, 0
+	for o <= len(ours) {
+		nextO := o
+		for nextO < len(ours) && match[nextO] == -1 {
+			nextO++
+		}
+		nextT := len(theirs)
+		if nextO < len(ours) {
+			nextT = match[nextO]
+		}
+		for k := 0; o+k < nextO || t+k < nextT; k++ {
+			switch {
+			case o+k < nextO && t+k < nextT:
+				changes = append(changes, TaskChange{Kind: Modified, Ours: ours[o+k], Theirs: theirs[t+k]})
+			case o+k < nextO:
+				changes = append(changes, TaskChange{Kind: Removed, Ours: ours[o+k]})
+			default:
+				changes = append(changes, TaskChange{Kind: Added, Theirs: theirs[t+k]})
+			}
+		}
+		if nextO == len(ours) {
+			break
+		}
+		changes = append(changes, TaskChange{Kind: Unchanged, Ours: ours[nextO], Theirs: theirs[nextT]})
+		o, t = nextO+1, nextT+1
+	}
+	return changes
+}
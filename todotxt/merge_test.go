@@ -0,0 +1,62 @@
+package todotxt_test
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiffTasks(t *testing.T) {
+	a := todotxt.MustBuildItem(todotxt.WithDescription("a"))
+	b := todotxt.MustBuildItem(todotxt.WithDescription("b"))
+	bChanged := todotxt.MustBuildItem(todotxt.WithDescription("b changed"))
+	c := todotxt.MustBuildItem(todotxt.WithDescription("c"))
+	d := todotxt.MustBuildItem(todotxt.WithDescription("d"))
+
+	testCases := map[string]struct {
+		ours     []*todotxt.Item
+		theirs   []*todotxt.Item
+		expected []todotxt.TaskChange
+	}{
+		"equal lists": {
+			ours:   []*todotxt.Item{a, b},
+			theirs: []*todotxt.Item{a, b},
+			expected: []todotxt.TaskChange{
+				{Kind: todotxt.Unchanged, Ours: a, Theirs: a},
+				{Kind: todotxt.Unchanged, Ours: b, Theirs: b},
+			},
+		},
+		"modification": {
+			ours:   []*todotxt.Item{a, b, c},
+			theirs: []*todotxt.Item{a, bChanged, c},
+			expected: []todotxt.TaskChange{
+				{Kind: todotxt.Unchanged, Ours: a, Theirs: a},
+				{Kind: todotxt.Modified, Ours: b, Theirs: bChanged},
+				{Kind: todotxt.Unchanged, Ours: c, Theirs: c},
+			},
+		},
+		"addition and removal": {
+			ours:   []*todotxt.Item{a, b, c},
+			theirs: []*todotxt.Item{a, c, d},
+			expected: []todotxt.TaskChange{
+				{Kind: todotxt.Unchanged, Ours: a, Theirs: a},
+				{Kind: todotxt.Removed, Ours: b},
+				{Kind: todotxt.Unchanged, Ours: c, Theirs: c},
+				{Kind: todotxt.Added, Theirs: d},
+			},
+		},
+		"empty ours": {
+			ours:   []*todotxt.Item{},
+			theirs: []*todotxt.Item{a},
+			expected: []todotxt.TaskChange{
+				{Kind: todotxt.Added, Theirs: a},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, todotxt.DiffTasks(tc.ours, tc.theirs))
+		})
+	}
+}
@@ -33,6 +33,7 @@ type ReadFunc func() (*List, error)
 type Repo struct {
 	file         string
 	checksum     [20]byte
+	base         []byte
 	watcher      *fsnotify.Watcher
 	updateChan   []chan ReadFunc
 	fileLock     sync.Mutex
@@ -41,6 +42,10 @@ type Repo struct {
 	Decoder      *Decoder
 	DefaultHooks []Hook
 	Keep         int
+	// Name is a human readable name for the repo (e.g. the workspace it belongs to)
+	Name string
+	// Archive marks repos that contain archived tasks (i.e. a done.txt)
+	Archive bool
 }
 
 func NewRepo(dest string) *Repo {
@@ -49,38 +54,81 @@ func NewRepo(dest string) *Repo {
 	}
 }
 
+// File returns the path of the underlying todo.txt file
+func (t *Repo) File() string {
+	return t.file
+}
+
 func (t *Repo) Save(l *List) error {
+	return t.save(l.Tasks())
+}
+
+func (t *Repo) save(tasks []*Item) error {
 	t.fileLock.Lock()
 	defer t.fileLock.Unlock()
-	err := t.handleOptimisticLocking(l)
+	toWrite, merged, err := t.handleOptimisticLocking(tasks)
 	if err != nil {
 		return fmt.Errorf("could not save file %s: %w", t.file, err)
 	}
-	err = t.write(l)
+	err = t.write(toWrite)
 	if err != nil {
 		return fmt.Errorf("could not save todo list: %w", err)
 	}
+	if merged {
+		// The list of the caller lacks the changes of the other side. Remembering the
+		// tasks of the caller as base makes the next save of that list merge again.
+		return t.rememberBase(tasks)
+	}
 	return nil
 }
 
-func (t *Repo) handleOptimisticLocking(l *List) error {
+// handleOptimisticLocking returns the tasks that should be written to disk.
+// If the file was changed since the last read, it tries to merge both versions
+// and reports whether it did so.
+func (t *Repo) handleOptimisticLocking(tasks []*Item) ([]*Item, bool, error) {
 	if t.checksum == [20]byte{} {
-		return nil // This is a save without a prior read, so we don't need locking
+		return tasks, false, nil // This is a save without a prior read, so we don't need locking
 	}
 	currentData, err := t.load()
 	if errors.Is(err, fs.ErrNotExist) {
-		return nil
+		return tasks, false, nil
 	}
 	if err != nil {
-		return fmt.Errorf("could not determine checksum of current state")
+		return nil, false, fmt.Errorf("could not determine checksum of current state")
+	}
+	if sha1.Sum(currentData) == t.checksum {
+		return tasks, false, nil
 	}
-	if sha1.Sum(currentData) != t.checksum {
-		return fmt.Errorf("locking error: %w", t.writeToAlternativeLocation(l))
+	if merged, ok := t.merge(tasks, currentData); ok {
+		return merged, true, nil
+	}
+	return nil, false, fmt.Errorf("locking error: %w", t.writeToAlternativeLocation(tasks))
+}
+
+// rememberBase makes tasks the state that the next save is compared against
+func (t *Repo) rememberBase(tasks []*Item) error {
+	buffer := bytes.Buffer{}
+	if err := t.encoder().Encode(&buffer, tasks); err != nil {
+		return fmt.Errorf("could not encode merge base: %w", err)
 	}
+	t.checksum = sha1.Sum(buffer.Bytes())
+	t.base = buffer.Bytes()
 	return nil
 }
 
-func (t *Repo) writeToAlternativeLocation(l *List) (err error) {
+func (t *Repo) merge(tasks []*Item, currentData []byte) ([]*Item, bool) {
+	base, err := t.decoder().Decode(bytes.NewReader(t.base))
+	if err != nil {
+		return nil, false
+	}
+	theirs, err := t.decoder().Decode(bytes.NewReader(currentData))
+	if err != nil {
+		return nil, false
+	}
+	return merge3(base, tasks, theirs)
+}
+
+func (t *Repo) writeToAlternativeLocation(tasks []*Item) (err error) {
 	extension := path.Ext(t.file)
 	fileName := strings.TrimSuffix(path.Base(t.file), extension)
 	tmp, err := os.CreateTemp(path.Dir(t.file), fmt.Sprintf("%s.quest-conflict-*%s", fileName, extension))
@@ -90,7 +138,7 @@ func (t *Repo) writeToAlternativeLocation(l *List) (err error) {
 	defer func() {
 		err = errors.Join(err, tmp.Close())
 	}()
-	err = t.encoder().Encode(tmp, l.Tasks())
+	err = t.encoder().Encode(tmp, tasks)
 	if err != nil {
 		return err
 	}
@@ -99,7 +147,7 @@ func (t *Repo) writeToAlternativeLocation(l *List) (err error) {
 	}
 }
 
-func (t *Repo) write(l *List) error {
+func (t *Repo) write(tasks []*Item) error {
 	err := t.backup()
 	if err != nil {
 		return fmt.Errorf("backup failed: %w", err)
@@ -111,7 +159,7 @@ func (t *Repo) write(l *List) error {
 	}
 
 	buffer := bytes.Buffer{}
-	err = t.encoder().Encode(io.MultiWriter(tmp, &buffer), l.Tasks())
+	err = t.encoder().Encode(io.MultiWriter(tmp, &buffer), tasks)
 	if err != nil {
 		_ = tmp.Close()
 		return fmt.Errorf("could not write txt file %s: %w", t.file, err)
@@ -123,6 +171,7 @@ func (t *Repo) write(l *List) error {
 		return fmt.Errorf("could not move tmp file to final location: %w", err)
 	}
 	t.checksum = sha1.Sum(buffer.Bytes())
+	t.base = buffer.Bytes()
 	return nil
 }
 
@@ -165,7 +214,9 @@ func (t *Repo) Read() (*List, error) {
 		return nil, fmt.Errorf("could not parse txt file %s: %w", t.file, err)
 	}
 	list := ListOf(tasks...)
+	list.SetSource(t, tasks...)
 	t.checksum = sha1.Sum(rawData)
+	t.base = rawData
 	for _, b := range t.DefaultHooks {
 		list.AddHook(b)
 	}
@@ -29,21 +29,100 @@ func Test_ItemsAreOrderedCorrectly(t *testing.T) {
 	assert.Equal(t, "item 2", list.GetLine(3).Description())
 }
 
-func Test_OptimisticLockingReturnsErrorOnSaveIfWrittenInTheMeantime(t *testing.T) {
+func Test_OptimisticLockingReturnsErrorOnSaveIfChangesOverlap(t *testing.T) {
 	file := createTestFile(t, `A todo item`)
 	repo := todotxt.NewRepo(file)
 
 	list, err := repo.Read()
 	assert.Nil(t, err)
-	appendNewTask(t, file, "A new todo item")
-	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(file, []byte("A changed todo item\n"), 0644))
+	assert.Nil(t, list.GetLine(1).EditDescription("A todo item that we changed"))
 	err = repo.Save(list)
 
 	var oError todotxt.OLockError
 	assert.ErrorAs(t, err, &oError)
 	content, err := os.ReadFile(oError.BackupPath)
 	assert.Nil(t, err)
-	assert.Equal(t, "A todo item\n", string(content))
+	assert.Equal(t, "A todo item that we changed\n", string(content))
+	content, err = os.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "A changed todo item\n", string(content))
+}
+
+func Test_OptimisticLockingMergesChangesIfTheyDoNotOverlap(t *testing.T) {
+	testCases := map[string]struct {
+		base     string
+		theirs   string
+		ours     func(*todotxt.List) error
+		expected string
+	}{
+		"they appended, we changed": {
+			base:   "item 1\nitem 2\nitem 3",
+			theirs: "item 1\nitem 2\nitem 3\nitem 4",
+			ours: func(l *todotxt.List) error {
+				return l.GetLine(1).PrioritizeAs(todotxt.PrioA)
+			},
+			expected: "(A) item 1\nitem 2\nitem 3\nitem 4\n",
+		},
+		"both appended": {
+			base:   "item 1",
+			theirs: "item 1\ntheir item",
+			ours: func(l *todotxt.List) error {
+				return l.Add(todotxt.MustBuildItem(todotxt.WithDescription("our item")))
+			},
+			expected: "item 1\nour item\ntheir item\n",
+		},
+		"they removed, we changed another item": {
+			base:   "item 1\nitem 2\nitem 3",
+			theirs: "item 1\nitem 3",
+			ours: func(l *todotxt.List) error {
+				return l.GetLine(3).PrioritizeAs(todotxt.PrioA)
+			},
+			expected: "item 1\n(A) item 3\n",
+		},
+		"both made the same change": {
+			base:   "item 1\nitem 2",
+			theirs: "item 1\n(B) item 2",
+			ours: func(l *todotxt.List) error {
+				return l.GetLine(2).PrioritizeAs(todotxt.PrioB)
+			},
+			expected: "item 1\n(B) item 2\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			file := createTestFile(t, tc.base)
+			repo := todotxt.NewRepo(file)
+
+			list, err := repo.Read()
+			assert.Nil(t, err)
+			assert.Nil(t, os.WriteFile(file, []byte(tc.theirs), 0644))
+			assert.Nil(t, tc.ours(list))
+			assert.Nil(t, repo.Save(list))
+
+			content, err := os.ReadFile(file)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, string(content))
+		})
+	}
+}
+
+func Test_SavingAMergedListAgainKeepsTheMergedChanges(t *testing.T) {
+	file := createTestFile(t, "item 1\nitem 2")
+	repo := todotxt.NewRepo(file)
+
+	list, err := repo.Read()
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(file, []byte("item 1\nitem 2\ntheir item\n"), 0644))
+	assert.Nil(t, list.GetLine(1).PrioritizeAs(todotxt.PrioA))
+	assert.Nil(t, repo.Save(list))
+	assert.Nil(t, list.GetLine(2).PrioritizeAs(todotxt.PrioB))
+	assert.Nil(t, repo.Save(list))
+
+	content, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, "(A) item 1\n(B) item 2\ntheir item\n", string(content))
 }
 
 func Test_OptimisticLockingDoesNotReturnErrorIfFileWasNotChanged(t *testing.T) {
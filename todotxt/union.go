@@ -0,0 +1,124 @@
+package todotxt
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// Union combines the tasks of multiple repos into a single list.
+// When saving, every task is written back to the repo it was read from.
+// Tasks that have not been read from any of the repos (e.g. new tasks) are written to the first repo.
+type Union struct {
+	repos []*Repo
+}
+
+func NewUnion(repos ...*Repo) *Union {
+	return &Union{
+		repos: repos,
+	}
+}
+
+// Repos returns the repos of the union. The first one is the one that receives new tasks.
+func (u *Union) Repos() []*Repo {
+	return slices.Clone(u.repos)
+}
+
+// RepoNamed returns the repo with the given name or nil if there is none.
+func (u *Union) RepoNamed(name string) *Repo {
+	for _, r := range u.repos {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Read reads all repos and returns the concatenation of their tasks.
+// The hooks of the first repo are attached to the resulting list.
+func (u *Union) Read() (*List, error) {
+	list := &List{}
+	for _, r := range u.repos {
+		l, err := r.Read()
+		if err != nil {
+			return nil, err
+		}
+		tasks := l.Tasks()
+		if err := list.Add(tasks...); err != nil {
+			return nil, err
+		}
+		list.SetSource(r, tasks...)
+	}
+	if len(u.repos) > 0 {
+		for _, h := range u.repos[0].DefaultHooks {
+			list.AddHook(h)
+		}
+	}
+	return list, list.validate()
+}
+
+func (u *Union) Save(l *List) error {
+	if len(u.repos) == 0 {
+		return errors.New("can not save an empty union")
+	}
+	tasks := make(map[*Repo][]*Item)
+	for _, r := range u.repos {
+		tasks[r] = make([]*Item, 0)
+	}
+	for _, t := range l.Tasks() {
+		source := l.SourceOf(t)
+		if _, ok := tasks[source]; !ok {
+			source = u.repos[0]
+			l.SetSource(source, t)
+		}
+		tasks[source] = append(tasks[source], t)
+	}
+	for _, r := range u.repos {
+		if err := r.save(tasks[r]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch watches all repos of the union for changes. See Repo.Watch for details.
+func (u *Union) Watch() (<-chan ReadFunc, func(), error) {
+	updates := make(chan ReadFunc)
+	ends := make([]func(), 0, len(u.repos))
+	wg := sync.WaitGroup{}
+	for _, r := range u.repos {
+		data, end, err := r.Watch()
+		if err != nil {
+			for _, e := range ends {
+				e()
+			}
+			return nil, nil, err
+		}
+		ends = append(ends, end)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range data {
+				updates <- u.Read
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(updates)
+	}()
+	remove := func() {
+		for _, e := range ends {
+			e()
+		}
+	}
+	return updates, remove, nil
+}
+
+func (u *Union) Close() error {
+	errs := make([]error, 0, len(u.repos))
+	for _, r := range u.repos {
+		errs = append(errs, r.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package todotxt_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UnionWritesTasksBackToTheirOrigin(t *testing.T) {
+	work := createTestFile(t, "work task 1\nwork task 2\n")
+	private := path.Join(createTmpDir(t), "todo.txt")
+	assert.Nil(t, os.WriteFile(private, []byte("private task\n"), 0644))
+	workRepo, privateRepo := todotxt.NewRepo(work), todotxt.NewRepo(private)
+	workRepo.Name, privateRepo.Name = "work", "private"
+	union := todotxt.NewUnion(privateRepo, workRepo)
+
+	list, err := union.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, list.Len())
+	assert.Equal(t, privateRepo, list.SourceOf(list.GetLine(1)))
+	assert.Equal(t, workRepo, list.SourceOf(list.GetLine(3)))
+
+	assert.Nil(t, list.GetLine(2).EditDescription("work task 1 changed"))
+	assert.Nil(t, list.Remove(3))
+	assert.Nil(t, list.Add(todotxt.MustBuildItem(todotxt.WithDescription("new task"))))
+	assert.Nil(t, union.Save(list))
+
+	workContent, err := os.ReadFile(work)
+	assert.Nil(t, err)
+	privateContent, err := os.ReadFile(private)
+	assert.Nil(t, err)
+	assert.Equal(t, "work task 1 changed\n", string(workContent))
+	assert.Equal(t, "private task\nnew task\n", string(privateContent))
+}
+
+func Test_UnionRepoNamed(t *testing.T) {
+	repo := todotxt.NewRepo(createTestFile(t, ""))
+	repo.Name = "work"
+	union := todotxt.NewUnion(repo)
+
+	assert.Equal(t, repo, union.RepoNamed("work"))
+	assert.Nil(t, union.RepoNamed("private"))
+}
@@ -7,17 +7,18 @@ import (
 )
 
 // AssertItemEqual compares two items
-// This is mainly needed, because reflect.DeppeEqual can't compare function field, but Item has one
+// This is mainly needed, because reflect.DeppeEqual can't compare function field, but Item has one.
+// The memoized description is ignored as well, because it depends on how the item was built.
 func AssertItemEqual(t *testing.T, expected *Item, actual *Item) {
 	if expected != nil {
-		emitFuncBeforeExpected := expected.emitFunc
-		expected.emitFunc = nil
-		defer func() { expected.emitFunc = emitFuncBeforeExpected }()
+		emitFuncBeforeExpected, parsedBeforeExpected := expected.emitFunc, expected.parsed
+		expected.emitFunc, expected.parsed = nil, nil
+		defer func() { expected.emitFunc, expected.parsed = emitFuncBeforeExpected, parsedBeforeExpected }()
 	}
 	if actual != nil {
-		emitFuncBeforeActual := actual.emitFunc
-		actual.emitFunc = nil
-		defer func() { actual.emitFunc = emitFuncBeforeActual }()
+		emitFuncBeforeActual, parsedBeforeActual := actual.emitFunc, actual.parsed
+		actual.emitFunc, actual.parsed = nil, nil
+		defer func() { actual.emitFunc, actual.parsed = emitFuncBeforeActual, parsedBeforeActual }()
 	}
 	assert.Equal(t, expected, actual)
 }
@@ -0,0 +1,393 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Actions are the modifications that can be triggered from within an interactive list.
+// They apply to the marked tasks or, if nothing is marked, to the task under the cursor.
+// Actions that are nil are not available.
+type Actions struct {
+	// Save writes the list back to its files. It is called after every action with a description
+	// of the action (e.g. "Toggled 2 task(s)"). Without it the list is read-only.
+	Save func(list *todotxt.List, action string) error
+	// Add adds a new task with the given description to the list.
+	Add func(list *todotxt.List, description string) (*todotxt.Item, error)
+	// Archive moves the given done tasks out of the list.
+	Archive func(list *todotxt.List, items []*todotxt.Item) error
+	// Track starts tracking the given task.
+	Track func(list *todotxt.List, item *todotxt.Item) error
+	// Notes opens the note of the given task. It runs while the list releases the terminal.
+	Notes func(list *todotxt.List, item *todotxt.Item) error
+	// Notifications sets where notifications of the hooks are sent to. While the list runs they are shown in the status line.
+	Notifications func(notify func(msg string))
+}
+
+type listKeyMap struct {
+	Filter     key.Binding
+	Sort       key.Binding
+	Columns    key.Binding
+	Mark       key.Binding
+	Unmark     key.Binding
+	Complete   key.Binding
+	Prioritize key.Binding
+	SetTag     key.Binding
+	UnsetTag   key.Binding
+	Edit       key.Binding
+	Add        key.Binding
+	Archive    key.Binding
+	Track      key.Binding
+	Notes      key.Binding
+	Quit       key.Binding
+}
+
+var defaultListKeyMap = listKeyMap{
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "Filter"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "Sort"),
+	),
+	Columns: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "Columns"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "Mark"),
+	),
+	Unmark: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "Unmark all"),
+	),
+	Complete: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "Toggle done"),
+	),
+	Prioritize: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "Prioritize"),
+	),
+	SetTag: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "Set tag"),
+	),
+	UnsetTag: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "Unset tag"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "Edit"),
+	),
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "Add"),
+	),
+	Archive: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "Archive"),
+	),
+	Track: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "Track"),
+	),
+	Notes: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "Notes"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c", "q"),
+		key.WithHelp("q", "Quit"),
+	),
+}
+
+var promptKeyMap = struct {
+	Confirm key.Binding
+	Cancel  key.Binding
+}{
+	Confirm: key.NewBinding(key.WithKeys("enter")),
+	Cancel:  key.NewBinding(key.WithKeys("esc", "ctrl+c")),
+}
+
+type promptKind int
+
+const (
+	promptAdd promptKind = iota
+	promptEdit
+	promptPriority
+	promptSetTag
+	promptUnsetTag
+	promptFilter
+	promptSort
+	promptProjection
+)
+
+// live reports whether the input of the prompt is applied while typing
+func (k promptKind) live() bool {
+	return k == promptFilter || k == promptSort || k == promptProjection
+}
+
+// prompt asks for the input of an action, that is applied to targets afterwards
+type prompt struct {
+	kind    promptKind
+	input   textinput.Model
+	targets []*todotxt.Item
+	// hint explains why the input of a live prompt could not be applied
+	hint string
+	// previous is restored when a live prompt is cancelled
+	previous liveState
+}
+
+func newPrompt(kind promptKind, title string, value string, targets []*todotxt.Item) *prompt {
+	input := textinput.New()
+	input.Prompt = title + ": "
+	input.SetValue(value)
+	input.CursorEnd()
+	input.Focus()
+	return &prompt{
+		kind:    kind,
+		input:   input,
+		targets: targets,
+	}
+}
+
+// execFunc runs a function while bubbletea releases the terminal
+type execFunc func() error
+
+func (e execFunc) Run() error          { return e() }
+func (e execFunc) SetStdin(io.Reader)  {}
+func (e execFunc) SetStdout(io.Writer) {}
+func (e execFunc) SetStderr(io.Writer) {}
+
+type execDoneMsg struct {
+	err error
+}
+
+// helpKeys returns the bindings that are available in the list
+func (l List) helpKeys() []key.Binding {
+	keys := []key.Binding{defaultListKeyMap.Filter}
+	if l.compileSort != nil {
+		keys = append(keys, defaultListKeyMap.Sort)
+	}
+	keys = append(keys, defaultListKeyMap.Columns)
+	if l.actionsEnabled() {
+		keys = append(keys, defaultListKeyMap.Mark, defaultListKeyMap.Unmark, defaultListKeyMap.Complete, defaultListKeyMap.Prioritize,
+			defaultListKeyMap.SetTag, defaultListKeyMap.UnsetTag, defaultListKeyMap.Edit)
+		if l.actions.Add != nil {
+			keys = append(keys, defaultListKeyMap.Add)
+		}
+		if l.actions.Archive != nil {
+			keys = append(keys, defaultListKeyMap.Archive)
+		}
+		if l.actions.Track != nil {
+			keys = append(keys, defaultListKeyMap.Track)
+		}
+		if l.actions.Notes != nil {
+			keys = append(keys, defaultListKeyMap.Notes)
+		}
+	}
+	return append(keys, defaultListKeyMap.Quit)
+}
+
+func (l List) handleQueryKey(msg tea.KeyMsg) (List, bool) {
+	switch {
+	case key.Matches(msg, defaultListKeyMap.Filter):
+		return l.startLivePrompt(promptFilter), true
+	case key.Matches(msg, defaultListKeyMap.Sort) && l.compileSort != nil:
+		return l.startLivePrompt(promptSort), true
+	case key.Matches(msg, defaultListKeyMap.Columns):
+		return l.startLivePrompt(promptProjection), true
+	}
+	return l, false
+}
+
+func (l List) handleActionKey(msg tea.KeyMsg) (List, tea.Cmd, bool) {
+	a := l.actions
+	targets := l.targets()
+	switch {
+	case key.Matches(msg, defaultListKeyMap.Mark):
+		if item := l.itemAtCursor(); item != nil {
+			if _, ok := l.marked[item]; ok {
+				delete(l.marked, item)
+			} else {
+				l.marked[item] = struct{}{}
+			}
+			l.table.MoveDown(1)
+		}
+		l.table.UpdateViewport()
+	case key.Matches(msg, defaultListKeyMap.Unmark):
+		clear(l.marked)
+		l.table.UpdateViewport()
+	case key.Matches(msg, defaultListKeyMap.Complete) && len(targets) > 0:
+		l = l.apply("Toggled", targets, func(i *todotxt.Item) error {
+			if i.Done() {
+				return i.MarkUndone()
+			}
+			return i.Complete()
+		})
+	case key.Matches(msg, defaultListKeyMap.Prioritize) && len(targets) > 0:
+		l.prompt = newPrompt(promptPriority, "Priority (A-Z, empty to remove)", "", targets)
+	case key.Matches(msg, defaultListKeyMap.SetTag) && len(targets) > 0:
+		l.prompt = newPrompt(promptSetTag, "Set tag (key:value)", "", targets)
+	case key.Matches(msg, defaultListKeyMap.UnsetTag) && len(targets) > 0:
+		l.prompt = newPrompt(promptUnsetTag, "Unset tag", "", targets)
+	case key.Matches(msg, defaultListKeyMap.Edit) && l.itemAtCursor() != nil:
+		item := l.itemAtCursor()
+		l.prompt = newPrompt(promptEdit, "Description", item.Description(), []*todotxt.Item{item})
+	case key.Matches(msg, defaultListKeyMap.Add) && a.Add != nil:
+		l.prompt = newPrompt(promptAdd, "New task", "", nil)
+	case key.Matches(msg, defaultListKeyMap.Archive) && a.Archive != nil && len(targets) > 0:
+		done := make([]*todotxt.Item, 0, len(targets))
+		for _, t := range targets {
+			if t.Done() {
+				done = append(done, t)
+			}
+		}
+		if len(done) == 0 {
+			l.status = "Only done tasks can be archived"
+			break
+		}
+		l = l.applyAll("Archived", len(done), func() error { return a.Archive(l.list, done) })
+	case key.Matches(msg, defaultListKeyMap.Track) && a.Track != nil && l.itemAtCursor() != nil:
+		item := l.itemAtCursor()
+		l = l.apply("Started tracking", []*todotxt.Item{item}, func(i *todotxt.Item) error { return a.Track(l.list, i) })
+	case key.Matches(msg, defaultListKeyMap.Notes) && a.Notes != nil && l.itemAtCursor() != nil:
+		item, list := l.itemAtCursor(), l.list
+		return l, tea.Exec(execFunc(func() error { return a.Notes(list, item) }), func(err error) tea.Msg {
+			return execDoneMsg{err: err}
+		}), true
+	default:
+		return l, nil, false
+	}
+	return l, nil, true
+}
+
+func (l List) updatePrompt(msg tea.Msg) (List, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	switch {
+	case ok && key.Matches(keyMsg, promptKeyMap.Cancel):
+		p := l.prompt
+		l.prompt = nil
+		if p.kind.live() {
+			l = l.restore(p.previous)
+		}
+		return l, nil
+	case ok && key.Matches(keyMsg, promptKeyMap.Confirm):
+		p := l.prompt
+		l.prompt = nil
+		if p.kind.live() {
+			// Invalid input is discarded
+			if p.hint != "" {
+				l = l.restore(p.previous)
+				l.status = fmt.Sprintf("Error: %s", p.hint)
+			}
+			return l, nil
+		}
+		return l.submitPrompt(p.kind, strings.TrimSpace(p.input.Value()), p.targets), nil
+	}
+	var cmd tea.Cmd
+	previousValue := l.prompt.input.Value()
+	l.prompt.input, cmd = l.prompt.input.Update(msg)
+	if l.prompt.kind.live() && l.prompt.input.Value() != previousValue {
+		l = l.preview()
+	}
+	return l, cmd
+}
+
+func (l List) submitPrompt(kind promptKind, value string, targets []*todotxt.Item) List {
+	switch kind {
+	case promptAdd:
+		if value == "" {
+			return l
+		}
+		var added *todotxt.Item
+		l = l.applyAll("Added", 1, func() (err error) {
+			added, err = l.actions.Add(l.list, value)
+			return err
+		})
+		return l.moveCursorToItem(l.list, added)
+	case promptEdit:
+		if value == "" {
+			l.status = "The description can not be empty"
+			return l
+		}
+		return l.apply("Edited", targets, func(i *todotxt.Item) error { return i.EditDescription(value) })
+	case promptPriority:
+		if value == "" {
+			value = "none"
+		}
+		prio, err := todotxt.PriorityFromString(value)
+		if err != nil {
+			l.status = fmt.Sprintf("Error: %s", err)
+			return l
+		}
+		return l.apply("Prioritized", targets, func(i *todotxt.Item) error { return i.PrioritizeAs(prio) })
+	case promptSetTag:
+		tagKey, tagValue, ok := strings.Cut(value, ":")
+		if !ok || tagKey == "" || tagValue == "" || strings.ContainsAny(value, " \t") {
+			l.status = "Expected a tag of the form key:value"
+			return l
+		}
+		return l.apply("Updated", targets, func(i *todotxt.Item) error { return i.SetTag(tagKey, tagValue) })
+	case promptUnsetTag:
+		if value == "" {
+			return l
+		}
+		return l.apply("Updated", targets, func(i *todotxt.Item) error { return i.SetTag(value, "") })
+	}
+	return l
+}
+
+// apply runs action for every target and saves the list afterwards.
+// Tasks that were modified before an action fails are saved nonetheless.
+func (l List) apply(verb string, targets []*todotxt.Item, action func(*todotxt.Item) error) List {
+	return l.applyAll(verb, len(targets), func() error {
+		for _, t := range targets {
+			if err := action(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyAll runs action, which modifies count tasks, and saves the list afterwards.
+func (l List) applyAll(verb string, count int, action func() error) List {
+	description := fmt.Sprintf("%s %d task(s)", verb, count)
+	actionErr := action()
+	saveErr := l.actions.Save(l.list, description)
+	clear(l.marked)
+	l = l.refreshTable(l.list)
+	switch err := errors.Join(actionErr, saveErr); {
+	case err != nil:
+		l.status = fmt.Sprintf("Error: %s", err)
+	default:
+		l.status = description
+	}
+	return l
+}
+
+// targets returns the marked tasks or the task under the cursor if nothing is marked
+func (l List) targets() []*todotxt.Item {
+	targets := make([]*todotxt.Item, 0, len(l.marked))
+	for _, t := range l.selection {
+		if _, ok := l.marked[t]; ok {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 && l.itemAtCursor() != nil {
+		targets = append(targets, l.itemAtCursor())
+	}
+	return targets
+}
@@ -0,0 +1,108 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/mattn/go-runewidth"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+var shades = []rune("·░▒▓█")
+
+// Sparklines renders one sparkline per series. Every series is scaled to the
+// maximum of all series, so that they can be compared with each other.
+func Sparklines(series []qstats.Series) string {
+	labelWidth, maxValue := 0, 0
+	for _, s := range series {
+		labelWidth = max(labelWidth, runewidth.StringWidth(s.Label))
+		for _, v := range s.Values {
+			maxValue = max(maxValue, v)
+		}
+	}
+	builder := strings.Builder{}
+	for _, s := range series {
+		builder.WriteString(runewidth.FillRight(s.Label, labelWidth))
+		builder.WriteString(" ")
+		for _, v := range s.Values {
+			builder.WriteRune(scale(sparks, v, maxValue))
+		}
+		if len(s.Values) > 0 {
+			fmt.Fprintf(&builder, " %d", s.Values[len(s.Values)-1])
+		}
+		builder.WriteString("\n")
+	}
+	if len(series) > 0 && len(series[0].Starts) > 0 {
+		first, last := series[0].Starts[0], series[0].Starts[len(series[0].Starts)-1]
+		fmt.Fprintf(&builder, "%s %s - %s\n", strings.Repeat(" ", labelWidth), first, last)
+	}
+	return builder.String()
+}
+
+// BarChart renders a horizontal bar for every value. The longest bar has the given width.
+func BarChart(labels []string, values []int, width int) string {
+	labelWidth, maxValue := 0, 0
+	for i := range labels {
+		labelWidth = max(labelWidth, runewidth.StringWidth(labels[i]))
+		maxValue = max(maxValue, values[i])
+	}
+	builder := strings.Builder{}
+	for i := range labels {
+		length := 0
+		if maxValue > 0 {
+			length = values[i] * width / maxValue
+		}
+		fmt.Fprintf(&builder, "%s %s %d\n", runewidth.FillRight(labels[i], labelWidth), strings.Repeat("█", length), values[i])
+	}
+	return builder.String()
+}
+
+// Heatmap renders a contribution heatmap with one column per week and one row per weekday.
+func Heatmap(perDay map[time.Time]int, begin time.Time, end time.Time) string {
+	begin, end = qstats.Week.Start(begin), qstats.Day.Start(end)
+	maxValue := 0
+	for day, v := range perDay {
+		if !day.Before(begin) && !day.After(end) {
+			maxValue = max(maxValue, v)
+		}
+	}
+	weekdays := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	builder := strings.Builder{}
+	builder.WriteString("    ")
+	// Label every month at the first week that starts in it
+	month := begin.Month()
+	for week := begin; !week.After(end); week = qstats.Week.Next(week) {
+		if week.Month() != month {
+			month = week.Month()
+			builder.WriteString(week.Format("Jan"))
+			week = qstats.Week.Next(qstats.Week.Next(week))
+			continue
+		}
+		builder.WriteString(" ")
+	}
+	builder.WriteString("\n")
+	for d, name := range weekdays {
+		builder.WriteString(name + " ")
+		for week := begin; !week.After(end); week = qstats.Week.Next(week) {
+			day := week.AddDate(0, 0, d)
+			if day.After(end) {
+				builder.WriteString(" ")
+				continue
+			}
+			builder.WriteRune(scale(shades, perDay[day], maxValue))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// scale maps value to one of the given symbols. Zero always maps to the first symbol
+// and every value > 0 to one of the other symbols.
+func scale(symbols []rune, value int, maxValue int) rune {
+	if value <= 0 || maxValue <= 0 {
+		return symbols[0]
+	}
+	return symbols[1+(value*(len(symbols)-1)-1)/maxValue]
+}
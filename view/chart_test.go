@@ -0,0 +1,73 @@
+package view
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SparklinesAreScaledToTheMaximumOfAllSeries(t *testing.T) {
+	series := []qstats.Series{
+		{Label: "open", Starts: []string{"2022-01-01", "2022-01-02"}, Values: []int{0, 4}},
+		{Label: "done", Starts: []string{"2022-01-01", "2022-01-02"}, Values: []int{2, 1}},
+	}
+
+	assert.Equal(t, "open ▁█ 4\ndone ▅▃ 1\n     2022-01-01 - 2022-01-02\n", Sparklines(series))
+}
+
+func Test_SparklinesOfASingleValue(t *testing.T) {
+	series := []qstats.Series{{Label: "open", Starts: []string{"2022-01-01"}, Values: []int{3}}}
+
+	assert.Equal(t, "open █ 3\n     2022-01-01 - 2022-01-01\n", Sparklines(series))
+}
+
+func Test_SparklinesOfEmptySeries(t *testing.T) {
+	assert.Equal(t, "", Sparklines(nil))
+	assert.Equal(t, "open \n", Sparklines([]qstats.Series{{Label: "open"}}))
+}
+
+func Test_BarChartScalesTheLongestBarToWidth(t *testing.T) {
+	chart := BarChart([]string{"Mon", "Tuesday"}, []int{2, 4}, 4)
+
+	assert.Equal(t, "Mon     ██ 2\nTuesday ████ 4\n", chart)
+}
+
+func Test_BarChartOfASingleValue(t *testing.T) {
+	assert.Equal(t, "Mon ███ 1\n", BarChart([]string{"Mon"}, []int{1}, 3))
+}
+
+func Test_BarChartWithoutValues(t *testing.T) {
+	assert.Equal(t, "", BarChart(nil, nil, 10))
+	assert.Equal(t, "Mon  0\n", BarChart([]string{"Mon"}, []int{0}, 10))
+}
+
+func Test_HeatmapShowsOneColumnPerWeek(t *testing.T) {
+	monday := time.Date(2022, 1, 24, 0, 0, 0, 0, time.UTC)
+	perDay := map[time.Time]int{
+		monday:                  4,
+		monday.AddDate(0, 0, 1): 1,
+		monday.AddDate(0, 0, 7): 2,
+	}
+
+	heatmap := Heatmap(perDay, monday, monday.AddDate(0, 0, 8))
+
+	assert.Equal(t, "      \nMon █▒\nTue ░·\nWed · \nThu · \nFri · \nSat · \nSun · \n", heatmap)
+}
+
+func Test_HeatmapOfASingleDay(t *testing.T) {
+	monday := time.Date(2022, 1, 24, 0, 0, 0, 0, time.UTC)
+
+	heatmap := Heatmap(map[time.Time]int{monday: 1}, monday, monday)
+
+	assert.Equal(t, "     \nMon █\nTue  \nWed  \nThu  \nFri  \nSat  \nSun  \n", heatmap)
+}
+
+func Test_HeatmapWithoutValues(t *testing.T) {
+	monday := time.Date(2022, 1, 24, 0, 0, 0, 0, time.UTC)
+
+	heatmap := Heatmap(nil, monday, monday.AddDate(0, 0, 6))
+
+	assert.Equal(t, "     \nMon ·\nTue ·\nWed ·\nThu ·\nFri ·\nSat ·\nSun ·\n", heatmap)
+}
@@ -0,0 +1,232 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type conflictKeyMap struct {
+	Ours      key.Binding
+	Theirs    key.Binding
+	Toggle    key.Binding
+	AllOurs   key.Binding
+	AllTheirs key.Binding
+	Confirm   key.Binding
+	Cancel    key.Binding
+}
+
+func (c conflictKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{c.Ours, c.Theirs, c.Toggle, c.AllOurs, c.AllTheirs, c.Confirm, c.Cancel}
+}
+func (c conflictKeyMap) FullHelp() [][]key.Binding {
+	return nil
+}
+
+var defaultConflictKeyMap = conflictKeyMap{
+	Ours: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "Keep todo file"),
+	),
+	Theirs: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "Take conflict file"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("␣", "Toggle"),
+	),
+	AllOurs: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "Keep all"),
+	),
+	AllTheirs: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "Take all"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("⏎", "Confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("ctrl+c", "q"),
+		key.WithHelp("ctrl+c/q", "Cancel"),
+	),
+}
+
+var (
+	conflictChosenStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	conflictRejectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Strikethrough(true)
+)
+
+// ConflictResolution lets the user decide for every changed task
+// whether the version of the todo file or the version of the conflict file should be used.
+type ConflictResolution struct {
+	list      list.Model
+	help      help.Model
+	Cancelled bool
+}
+
+func NewConflictResolution(conflictFile string, changes []todotxt.TaskChange) ConflictResolution {
+	items := make([]list.Item, 0, len(changes))
+	for _, c := range changes {
+		if c.Kind == todotxt.Unchanged {
+			continue
+		}
+		// Tasks that have only been added in the conflict file are most likely wanted
+		items = append(items, conflictItem{change: c, theirs: c.Kind == todotxt.Added})
+	}
+	l := list.New(items, conflictItemDelegate{}, 0, 0)
+	l.SetShowHelp(false)
+	l.Styles = SelectionStyle
+	l.Title = fmt.Sprintf("Resolve conflicts with %s:", conflictFile)
+	l.SetFilteringEnabled(false)
+	l.SetShowStatusBar(false)
+	return ConflictResolution{
+		list: l,
+		help: help.New(),
+	}
+}
+
+// Run returns the changes for which the version of the conflict file was chosen.
+func (c ConflictResolution) Run() ([]todotxt.TaskChange, error) {
+	if len(c.list.Items()) == 0 {
+		return nil, nil
+	}
+	programme := tea.NewProgram(c)
+	finalModel, err := programme.Run()
+	if err != nil {
+		return nil, err
+	}
+	if finalModel.(ConflictResolution).Cancelled {
+		return nil, errors.New("operation cancelled by user")
+	}
+	return finalModel.(ConflictResolution).Accepted(), nil
+}
+
+func (c ConflictResolution) Accepted() []todotxt.TaskChange {
+	accepted := make([]todotxt.TaskChange, 0)
+	for _, item := range c.list.Items() {
+		if cItem := item.(conflictItem); cItem.theirs {
+			accepted = append(accepted, cItem.change)
+		}
+	}
+	return accepted
+}
+
+func (c ConflictResolution) Init() tea.Cmd {
+	return nil
+}
+
+func (c ConflictResolution) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.list.SetWidth(msg.Width)
+		c.list.SetHeight(min(msg.Height-1, 3*len(c.list.Items())+6))
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, defaultConflictKeyMap.Ours):
+			c = c.choose(func(bool) bool { return false }, false)
+		case key.Matches(msg, defaultConflictKeyMap.Theirs):
+			c = c.choose(func(bool) bool { return true }, false)
+		case key.Matches(msg, defaultConflictKeyMap.Toggle):
+			c = c.choose(func(b bool) bool { return !b }, false)
+		case key.Matches(msg, defaultConflictKeyMap.AllOurs):
+			c = c.choose(func(bool) bool { return false }, true)
+		case key.Matches(msg, defaultConflictKeyMap.AllTheirs):
+			c = c.choose(func(bool) bool { return true }, true)
+		case key.Matches(msg, defaultConflictKeyMap.Cancel):
+			c.Cancelled = true
+			return c, tea.Quit
+		case key.Matches(msg, defaultConflictKeyMap.Confirm):
+			return c, tea.Quit
+		}
+	}
+	var lcmd, hcmd tea.Cmd
+	c.list, lcmd = c.list.Update(msg)
+	c.help, hcmd = c.help.Update(msg)
+	return c, tea.Batch(lcmd, hcmd)
+}
+
+func (c ConflictResolution) View() string {
+	return lipgloss.JoinVertical(lipgloss.Left, c.list.View(), c.help.View(defaultConflictKeyMap))
+}
+
+func (c ConflictResolution) choose(f func(bool) bool, all bool) ConflictResolution {
+	items := c.list.Items()
+	for idx := range items {
+		if !all && idx != c.list.Index() {
+			continue
+		}
+		cItem := items[idx].(conflictItem)
+		cItem.theirs = f(cItem.theirs)
+		items[idx] = cItem
+	}
+	c.list.SetItems(items)
+	return c
+}
+
+type conflictItem struct {
+	change todotxt.TaskChange
+	theirs bool
+}
+
+func (c conflictItem) FilterValue() string {
+	return ""
+}
+
+type conflictItemDelegate struct{}
+
+func (d conflictItemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	cItem := item.(conflictItem)
+	var kind string
+	switch cItem.change.Kind {
+	case todotxt.Added:
+		kind = "added"
+	case todotxt.Removed:
+		kind = "removed"
+	case todotxt.Modified:
+		kind = "modified"
+	}
+	cursor := " "
+	if m.Index() == index {
+		cursor = ">"
+	}
+	ours, theirs := describe(cItem.change.Ours), describe(cItem.change.Theirs)
+	if cItem.theirs {
+		ours, theirs = conflictRejectedStyle.Render(ours), conflictChosenStyle.Render(theirs)
+	} else {
+		ours, theirs = conflictChosenStyle.Render(ours), conflictRejectedStyle.Render(theirs)
+	}
+	header := SelectionItemStyles.NormalTitle.Render(fmt.Sprintf("%s %s", cursor, kind))
+	if m.Index() == index {
+		header = SelectionItemStyles.SelectedTitle.Render(fmt.Sprintf("%s %s", cursor, kind))
+	}
+	_, _ = fmt.Fprintf(w, "%s\n    todo:     %s\n    conflict: %s", header, ours, theirs)
+}
+
+func describe(item *todotxt.Item) string {
+	if item == nil {
+		return "(none)"
+	}
+	return item.String()
+}
+
+func (d conflictItemDelegate) Height() int {
+	return 3
+}
+
+func (d conflictItemDelegate) Spacing() int {
+	return 0
+}
+
+func (d conflictItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	return nil
+}
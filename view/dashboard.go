@@ -0,0 +1,183 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	LayoutTabs    = "tabs"
+	LayoutColumns = "columns"
+	LayoutRows    = "rows"
+)
+
+var Layouts = []string{LayoutTabs, LayoutColumns, LayoutRows}
+
+var activeTitleStyle = lipgloss.NewStyle().Bold(true).Padding(0, 1).Background(lipgloss.Color("3")).Foreground(lipgloss.Color("0"))
+var inactiveTitleStyle = lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("8"))
+
+type dashboardKeyMap struct {
+	Next     key.Binding
+	Previous key.Binding
+}
+
+var defaultDashboardKeyMap = dashboardKeyMap{
+	Next: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "Next pane"),
+	),
+	Previous: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "Previous pane"),
+	),
+}
+
+// Dashboard shows several interactive lists at once. Keys are sent to the focused pane,
+// while changes of the underlying files refresh all panes together.
+type Dashboard struct {
+	repo            Watcher
+	layout          string
+	titles          []string
+	panes           []List
+	focus           int
+	help            help.Model
+	availableWidth  int
+	availableHeight int
+}
+
+// NewDashboard creates a dashboard that arranges the panes according to layout (see Layouts).
+// The panes must be interactive lists and there must be a title for each of them.
+func NewDashboard(repo Watcher, layout string, titles []string, panes []List) Dashboard {
+	embedded := make([]List, 0, len(panes))
+	for _, p := range panes {
+		embedded = append(embedded, p.Embedded())
+	}
+	return Dashboard{
+		repo:   repo,
+		layout: layout,
+		titles: titles,
+		panes:  embedded,
+		help:   help.New(),
+	}
+}
+
+func (d Dashboard) Run(initial *todotxt.List) error {
+	model, _ := d.Update(RefreshListMsg{List: initial})
+	// All panes belong to the same repo, so the notifications of the first one are sufficient
+	return runWatching(d.repo, model, d.panes[0].actions.Notifications)
+}
+
+func (d Dashboard) Init() tea.Cmd {
+	return nil
+}
+
+func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if d.panes[d.focus].prompt == nil {
+			switch {
+			case key.Matches(msg, defaultDashboardKeyMap.Next):
+				d.focus = (d.focus + 1) % len(d.panes)
+				return d, nil
+			case key.Matches(msg, defaultDashboardKeyMap.Previous):
+				d.focus = (d.focus + len(d.panes) - 1) % len(d.panes)
+				return d, nil
+			}
+		}
+		return d.updatePane(d.focus, msg)
+	case RefreshListMsg:
+		cmds := make([]tea.Cmd, 0, len(d.panes))
+		for i := range d.panes {
+			var cmd tea.Cmd
+			d, cmd = d.updatePane(i, msg)
+			cmds = append(cmds, cmd)
+		}
+		return d, tea.Batch(cmds...)
+	case tea.WindowSizeMsg:
+		d.availableWidth = msg.Width
+		d.availableHeight = msg.Height
+		d.help.Width = msg.Width
+		return d.resize()
+	}
+	// Everything else (e.g. the result of an action) belongs to the focused pane
+	return d.updatePane(d.focus, msg)
+}
+
+func (d Dashboard) updatePane(idx int, msg tea.Msg) (Dashboard, tea.Cmd) {
+	model, cmd := d.panes[idx].Update(msg)
+	d.panes = append([]List(nil), d.panes...)
+	d.panes[idx] = model.(List)
+	return d, cmd
+}
+
+// resize distributes the available space among the panes.
+// Every pane (or the tab bar) has a title line and the help is shown in the last line.
+func (d Dashboard) resize() (tea.Model, tea.Cmd) {
+	width, height := d.availableWidth, d.availableHeight-2
+	switch d.layout {
+	case LayoutColumns:
+		width = d.availableWidth / len(d.panes)
+	case LayoutRows:
+		height = (d.availableHeight-1)/len(d.panes) - 1
+	}
+	for i := range d.panes {
+		d, _ = d.updatePane(i, tea.WindowSizeMsg{Width: width, Height: max(0, height)})
+	}
+	return d, nil
+}
+
+func (d Dashboard) View() string {
+	builder := strings.Builder{}
+	switch d.layout {
+	case LayoutColumns:
+		columns := make([]string, 0, len(d.panes))
+		width := d.availableWidth / len(d.panes)
+		for i := range d.panes {
+			columns = append(columns, d.renderPane(i, width))
+		}
+		builder.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+	case LayoutRows:
+		for i := range d.panes {
+			builder.WriteString(d.renderPane(i, d.availableWidth))
+		}
+	default:
+		titles := make([]string, 0, len(d.panes))
+		for i := range d.panes {
+			titles = append(titles, d.title(i))
+		}
+		builder.WriteString(lipgloss.NewStyle().MaxWidth(d.availableWidth).Render(strings.Join(titles, " ")))
+		builder.WriteString("\n")
+		builder.WriteString(d.clip(d.panes[d.focus].View(), d.availableWidth))
+	}
+	keys := []key.Binding{defaultDashboardKeyMap.Next, defaultDashboardKeyMap.Previous}
+	builder.WriteString(d.help.ShortHelpView(append(keys, d.panes[d.focus].helpKeys()...)))
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func (d Dashboard) renderPane(idx int, width int) string {
+	return d.clip(d.title(idx)+"\n"+d.panes[idx].View(), width)
+}
+
+// clip cuts off (or pads) every line of content to exactly width, so that panes can be joined
+func (d Dashboard) clip(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+	truncated := lipgloss.NewStyle().MaxWidth(width).Render(strings.TrimSuffix(content, "\n"))
+	return lipgloss.PlaceHorizontal(width, lipgloss.Left, truncated) + "\n"
+}
+
+func (d Dashboard) title(idx int) string {
+	title := fmt.Sprintf("%s (%d)", d.titles[idx], len(d.panes[idx].selection))
+	if idx == d.focus {
+		return activeTitleStyle.Render(title)
+	}
+	return inactiveTitleStyle.Render(title)
+}
@@ -0,0 +1,93 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/todotxt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDashboard(layout string) (Dashboard, *todotxt.List, *int) {
+	saved := 0
+	all, list := newTestList(&saved)
+	done := all
+	done.marked = make(map[*todotxt.Item]struct{})
+	done.getTasks = func(l *todotxt.List) []*todotxt.Item {
+		selection := make([]*todotxt.Item, 0)
+		for _, i := range l.Tasks() {
+			if i.Done() {
+				selection = append(selection, i)
+			}
+		}
+		return selection
+	}
+	d := NewDashboard(nil, layout, []string{"all", "done"}, []List{all, done})
+	model, _ := d.Update(RefreshListMsg{List: list})
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	return model.(Dashboard), list, &saved
+}
+
+func pressInDashboard(d Dashboard, keys ...string) Dashboard {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "shift+tab":
+			msg = tea.KeyMsg{Type: tea.KeyShiftTab}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		model, _ := d.Update(msg)
+		d = model.(Dashboard)
+	}
+	return d
+}
+
+func Test_DashboardSendsKeysToTheFocusedPane(t *testing.T) {
+	d, list, saved := newTestDashboard(LayoutTabs)
+
+	d = pressInDashboard(d, "tab", "shift+tab", "x")
+
+	assert.Equal(t, 0, d.focus)
+	assert.True(t, list.Tasks()[0].Done())
+	assert.Equal(t, 1, *saved)
+	assert.Len(t, d.panes[1].selection, 0, "the other pane waits for the refresh")
+}
+
+func Test_DashboardRefreshesAllPanesTogether(t *testing.T) {
+	d, list, _ := newTestDashboard(LayoutColumns)
+
+	d = pressInDashboard(d, "x")
+	model, _ := d.Update(RefreshListMsg{List: list})
+	d = model.(Dashboard)
+
+	assert.Len(t, d.panes[0].selection, 3)
+	assert.Len(t, d.panes[1].selection, 1)
+	assert.Contains(t, d.View(), "all (3)")
+	assert.Contains(t, d.View(), "done (1)")
+}
+
+func Test_DashboardDoesNotSwitchPanesWhilePrompting(t *testing.T) {
+	d, _, _ := newTestDashboard(LayoutRows)
+
+	d = pressInDashboard(d, "tab", "/", "tab")
+
+	assert.Equal(t, 1, d.focus)
+	assert.NotNil(t, d.panes[1].prompt)
+	assert.Nil(t, d.panes[0].prompt)
+}
+
+func Test_DashboardShowsOnlyTheFocusedPaneAsTab(t *testing.T) {
+	d, _, _ := newTestDashboard(LayoutTabs)
+
+	d = pressInDashboard(d, "tab")
+
+	assert.Contains(t, d.View(), "all (3)")
+	assert.Contains(t, d.View(), "done (0)")
+	assert.Contains(t, d.View(), "no matches")
+	assert.NotContains(t, d.View(), "first task")
+}
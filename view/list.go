@@ -2,12 +2,16 @@ package view
 
 import (
 	"fmt"
+	"os"
 	"slices"
 	"strings"
 
 	"github.com/Fabian-G/quest/qprojection"
+	"github.com/Fabian-G/quest/qselect"
 	"github.com/Fabian-G/quest/todotxt"
 	"github.com/Fabian-G/quest/view/table"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
@@ -23,17 +27,38 @@ var nonInteractiveStyles = table.Styles{
 	Header:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
 	Cell:     lipgloss.NewStyle().Padding(0, 1),
 }
+var markedStyle = lipgloss.NewStyle().Reverse(true)
+var statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
 var detailsProjection = slices.DeleteFunc(slices.Clone(qprojection.StarProjection), func(s string) bool { return s == "tags" })
 
+// Watcher notifies about changes of the underlying todo.txt files
+type Watcher interface {
+	Watch() (<-chan todotxt.ReadFunc, func(), error)
+}
+
 type List struct {
 	list            *todotxt.List
-	repo            *todotxt.Repo
+	repo            Watcher
 	selection       []*todotxt.Item
+	depths          []int
+	parentOf        func(*todotxt.List, *todotxt.Item) *todotxt.Item
 	projection      []string
 	projector       qprojection.Projector
 	getTasks        func(*todotxt.List) []*todotxt.Item
+	limit           int
+	filter          string
+	filterFunc      qselect.Func
+	sortKeys        []string
+	sortFunc        func(*todotxt.Item, *todotxt.Item) int
+	compileSort     SortCompiler
 	table           table.Model
+	actions         Actions
+	marked          map[*todotxt.Item]struct{}
+	prompt          *prompt
+	status          string
+	help            help.Model
 	interactive     bool
+	embedded        bool
 	availableWidth  int
 	availableHeight int
 }
@@ -42,40 +67,61 @@ type RefreshListMsg struct {
 	List *todotxt.List
 }
 
-func NewList(repo *todotxt.Repo, proj qprojection.Projector, projection []string, getTasks func(*todotxt.List) []*todotxt.Item, interactive bool) List {
+// errorMsg reports an error that occurred outside of Update, e.g. in a script function of the query
+type errorMsg struct {
+	err error
+}
+
+// notificationMsg carries a notification of a hook, e.g. that a task is no longer blocked
+type notificationMsg struct {
+	msg string
+}
+
+func NewList(repo Watcher, proj qprojection.Projector, projection []string, getTasks func(*todotxt.List) []*todotxt.Item, interactive bool) List {
 	l := List{
 		repo:        repo,
 		projector:   proj,
 		projection:  projection,
 		getTasks:    getTasks,
 		interactive: interactive,
+		marked:      make(map[*todotxt.Item]struct{}),
+		help:        help.New(),
 	}
 
 	l.table = table.New()
 	return l
 }
 
+// AsTree renders subtasks indented below their parents. Tasks whose parent is
+// not part of the selection are shown at the top level.
+func (l List) AsTree(parentOf func(*todotxt.List, *todotxt.Item) *todotxt.Item) List {
+	l.parentOf = parentOf
+	return l
+}
+
+// WithActions enables the actions of an interactive list (see Actions).
+func (l List) WithActions(actions Actions) List {
+	l.actions = actions
+	return l
+}
+
+// Embedded hides the details and the help of an interactive list, so that
+// it can be shown as one of several panes (see Dashboard).
+func (l List) Embedded() List {
+	l.embedded = true
+	return l
+}
+
+func (l List) actionsEnabled() bool {
+	return l.interactive && l.actions.Save != nil
+}
+
 func (l List) Run(initial *todotxt.List) error {
 	model, _ := l.Update(RefreshListMsg{List: initial})
 	l = model.(List)
 	switch l.interactive {
 	case true:
-		programme := tea.NewProgram(l)
-		data, end, err := l.repo.Watch()
-		if err != nil {
-			return err
-		}
-		defer end()
-		go func() {
-			for update := range data {
-				newList, err := update()
-				if err != nil {
-					continue
-				}
-				programme.Send(RefreshListMsg{List: newList})
-			}
-		}()
-		if _, err := programme.Run(); err != nil {
+		if err := runWatching(l.repo, l, l.actions.Notifications); err != nil {
 			return err
 		}
 	default:
@@ -84,6 +130,43 @@ func (l List) Run(initial *todotxt.List) error {
 	return nil
 }
 
+// runWatching runs model until it quits and sends a RefreshListMsg whenever repo changes.
+// If notifications is set, the notifications of the hooks are sent to the model as well.
+// Errors of script functions are shown in the status line, because the terminal belongs to the model.
+func runWatching(repo Watcher, model tea.Model, notifications func(func(string))) error {
+	programme := tea.NewProgram(model)
+	data, end, err := repo.Watch()
+	if err != nil {
+		return err
+	}
+	defer end()
+	qselect.SetScriptErrorReporter(func(err error) {
+		// Scripts run within Update, so sending synchronously would block forever
+		go programme.Send(errorMsg{err: err})
+	})
+	defer qselect.SetScriptErrorReporter(func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+	})
+	if notifications != nil {
+		notifications(func(msg string) {
+			// Hooks run within Update as well
+			go programme.Send(notificationMsg{msg: msg})
+		})
+		defer notifications(nil)
+	}
+	go func() {
+		for update := range data {
+			newList, err := update()
+			if err != nil {
+				continue
+			}
+			programme.Send(RefreshListMsg{List: newList})
+		}
+	}()
+	_, err = programme.Run()
+	return err
+}
+
 func (l List) mapToColumns() ([]table.Row, []table.Column, func(table.Model, string, table.CellPosition) string) {
 	headings, data, styles := l.projector.MustProject(l.projection, l.list, l.selection) // It is the callers job to verify the projection
 	if len(headings) == 0 || len(data) == 0 {
@@ -92,24 +175,33 @@ func (l List) mapToColumns() ([]table.Row, []table.Column, func(table.Model, str
 	columns := make([]table.Column, 0, len(headings))
 	rows := make([]table.Row, len(data))
 	for i, h := range headings {
+		tree := l.depths != nil && h == "Description"
 		maxWidth := 0
 		values := make([]string, 0, len(data))
-		for _, val := range data {
+		for j, val := range data {
 			values = append(values, val[i])
-			maxWidth = max(maxWidth, len(val[i]))
+			if tree {
+				maxWidth = max(maxWidth, len(val[i])+l.depths[j]*len(table.TreeIndent))
+			} else {
+				maxWidth = max(maxWidth, len(val[i]))
+			}
 		}
 		if maxWidth == 0 {
 			styles = deleteColumn(styles, len(columns))
 			continue
 		}
 
-		columns = append(columns, table.Column{Title: h, Width: max(maxWidth, len(h))})
+		columns = append(columns, table.Column{Title: h, Width: max(maxWidth, len(h)), Tree: tree})
 		for i, v := range values {
 			rows[i] = append(rows[i], v)
 		}
 	}
+	marked, selection := l.marked, l.selection
 	return rows, columns, func(m table.Model, s string, cp table.CellPosition) string {
 		style := styles[cp.RowID][cp.Column]
+		if _, ok := marked[selection[cp.RowID]]; ok {
+			style = style.Copy().Inherit(markedStyle)
+		}
 		val := style.Padding(0, 1).Render(s)
 		return strings.ReplaceAll(val, "\x1b[0m", "\x1b[39m")
 	}
@@ -127,16 +219,41 @@ func (l List) Init() tea.Cmd {
 }
 
 func (l List) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.KeyMsg); ok && l.prompt != nil {
+		return l.updatePrompt(msg)
+	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" || msg.String() == "q" {
+		if key.Matches(msg, defaultListKeyMap.Quit) {
 			return l, tea.Quit
 		}
+		if l.interactive {
+			var handled bool
+			if l, handled = l.handleQueryKey(msg); handled {
+				return l, nil
+			}
+		}
+		if l.actionsEnabled() {
+			var cmd tea.Cmd
+			var handled bool
+			if l, cmd, handled = l.handleActionKey(msg); handled {
+				return l, cmd
+			}
+		}
+	case execDoneMsg:
+		if msg.err != nil {
+			l.status = fmt.Sprintf("Error: %s", msg.err)
+		}
+	case errorMsg:
+		l.status = fmt.Sprintf("Error: %s", msg.err)
+	case notificationMsg:
+		l.status = msg.msg
 	case RefreshListMsg:
 		l = l.refreshTable(msg.List)
 	case tea.WindowSizeMsg:
 		l.availableWidth = msg.Width
 		l.availableHeight = msg.Height
+		l.help.Width = msg.Width
 		l = l.updateSize()
 	}
 	m, cmd := l.table.Update(msg)
@@ -146,20 +263,44 @@ func (l List) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (l List) updateSize() List {
-	l.table.SetHeight(max(0, min(len(l.selection), l.availableHeight-len(detailsProjection)-3)))
+	reserved := len(detailsProjection) + 3
+	switch {
+	case l.embedded:
+		reserved = 2 // header and status (or prompt)
+	case l.interactive:
+		reserved += 2 // status (or prompt) and help
+	}
+	l.table.SetHeight(max(0, min(len(l.selection), l.availableHeight-reserved)))
 	return l
 }
 
 func (l List) View() string {
+	builder := strings.Builder{}
 	if len(l.selection) == 0 {
-		return "no matches\n"
+		builder.WriteString("no matches\n")
+	} else {
+		builder.WriteString(l.table.View())
+		builder.WriteString("\n")
+		if l.interactive && !l.embedded {
+			builder.WriteString("\n")
+			l.renderDetails(&builder)
+		}
 	}
-	builder := strings.Builder{}
-	builder.WriteString(l.table.View())
-	builder.WriteString("\n")
 	if l.interactive {
+		switch {
+		case l.prompt != nil:
+			builder.WriteString(l.prompt.input.View())
+			if l.prompt.hint != "" {
+				builder.WriteString(" " + hintStyle.Render(l.prompt.hint))
+			}
+		default:
+			builder.WriteString(statusStyle.Render(l.status))
+		}
 		builder.WriteString("\n")
-		l.renderDetails(&builder)
+		if !l.embedded {
+			builder.WriteString(l.help.ShortHelpView(l.helpKeys()))
+			builder.WriteString("\n")
+		}
 	}
 	return builder.String()
 }
@@ -187,18 +328,23 @@ func (l List) renderDetails(writer *strings.Builder) {
 }
 
 func (l List) refreshTable(list *todotxt.List) List {
-	previous := l.itemAtCursor()
+	previous, previousList := l.itemAtCursor(), l.list
 	l.list = list
-	l.selection = l.getTasks(list)
+	l.selection = l.applyLiveState(l.getTasks(list))
+	l = l.remapToList(previousList)
+	if l.parentOf != nil {
+		l.selection, l.depths = treeOrder(list, l.selection, l.parentOf)
+	}
 	rows, columns, renderCell := l.mapToColumns()
 	l.table.SetStyles(l.styles(renderCell))
 	l.table.SetRows(nil)
 	l.table.SetColumns(nil)
 	l.table.SetColumns(columns)
+	l.table.SetDepths(l.depths)
 	l.table.SetRows(rows)
 	if l.interactive {
 		l = l.updateSize()
-		l = l.moveCursorToItem(previous)
+		l = l.moveCursorToItem(previousList, previous)
 		l.table.Focus()
 	} else {
 		l.table.SetHeight(len(rows))
@@ -224,12 +370,60 @@ func (l List) itemAtCursor() *todotxt.Item {
 	return nil
 }
 
-func (l List) moveCursorToItem(target *todotxt.Item) List {
+// remapToList replaces the marked tasks and the targets of the prompt with their
+// counterparts in the current list, since a refresh creates new items.
+func (l List) remapToList(previous *todotxt.List) List {
+	marked := make(map[*todotxt.Item]struct{}, len(l.marked))
+	for item := range l.marked {
+		if counterpart := l.counterpart(previous, item); counterpart != nil && slices.Contains(l.selection, counterpart) {
+			marked[counterpart] = struct{}{}
+		}
+	}
+	l.marked = marked
+	if l.prompt != nil {
+		targets := make([]*todotxt.Item, 0, len(l.prompt.targets))
+		for _, target := range l.prompt.targets {
+			if counterpart := l.counterpart(previous, target); counterpart != nil {
+				targets = append(targets, counterpart)
+			}
+		}
+		l.prompt.targets = targets
+	}
+	return l
+}
+
+// counterpart returns the task of the current list that corresponds to item of the previous list or nil if there is none.
+// Tasks are identified by their id if the id tag is configured, otherwise by their line and content.
+func (l List) counterpart(previous *todotxt.List, item *todotxt.Item) *todotxt.Item {
+	if previous == l.list {
+		return item
+	}
+	if ids := item.Tags()[l.projector.IdTag]; l.projector.IdTag != "" && len(ids) > 0 {
+		idx := slices.IndexFunc(l.list.Tasks(), func(i *todotxt.Item) bool {
+			return slices.Equal(i.Tags()[l.projector.IdTag], ids)
+		})
+		if idx == -1 {
+			return nil
+		}
+		return l.list.Tasks()[idx]
+	}
+	if previous == nil {
+		return nil
+	}
+	line := previous.LineOf(item)
+	if line == 0 || line > l.list.Len() {
+		return nil
+	}
+	if candidate := l.list.GetLine(line); candidate != nil && candidate.String() == item.String() {
+		return candidate
+	}
+	return nil
+}
+
+func (l List) moveCursorToItem(previous *todotxt.List, target *todotxt.Item) List {
 	var positionOfItem = -1
 	if target != nil {
-		positionOfItem = slices.IndexFunc(l.selection, func(i *todotxt.Item) bool {
-			return i.Description() == target.Description()
-		})
+		positionOfItem = slices.Index(l.selection, l.counterpart(previous, target))
 	}
 	if positionOfItem == -1 {
 		l.table.SetCursor(min(l.table.Cursor(), max(0, len(l.selection)-1)))
@@ -238,3 +432,47 @@ func (l List) moveCursorToItem(target *todotxt.Item) List {
 	l.table.SetCursor(positionOfItem)
 	return l
 }
+
+// treeOrder moves every task of the selection directly below its parent, while keeping the order of siblings.
+// It returns the reordered selection and the depth of each task.
+func treeOrder(list *todotxt.List, selection []*todotxt.Item, parentOf func(*todotxt.List, *todotxt.Item) *todotxt.Item) ([]*todotxt.Item, []int) {
+	selected := make(map[*todotxt.Item]struct{}, len(selection))
+	for _, t := range selection {
+		selected[t] = struct{}{}
+	}
+	children := make(map[*todotxt.Item][]*todotxt.Item)
+	roots := make([]*todotxt.Item, 0)
+	for _, t := range selection {
+		if p := parentOf(list, t); p != nil {
+			if _, ok := selected[p]; ok {
+				children[p] = append(children[p], t)
+				continue
+			}
+		}
+		roots = append(roots, t)
+	}
+
+	ordered := make([]*todotxt.Item, 0, len(selection))
+	depths := make([]int, 0, len(selection))
+	visited := make(map[*todotxt.Item]struct{}, len(selection))
+	var visit func(t *todotxt.Item, depth int)
+	visit = func(t *todotxt.Item, depth int) {
+		if _, ok := visited[t]; ok {
+			return
+		}
+		visited[t] = struct{}{}
+		ordered = append(ordered, t)
+		depths = append(depths, depth)
+		for _, c := range children[t] {
+			visit(c, depth+1)
+		}
+	}
+	for _, r := range roots {
+		visit(r, 0)
+	}
+	// Tasks that are part of a cycle are not reachable from a root
+	for _, t := range selection {
+		visit(t, 0)
+	}
+	return ordered, depths
+}
@@ -0,0 +1,234 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/Fabian-G/quest/qprojection"
+	"github.com/Fabian-G/quest/qsort"
+	"github.com/Fabian-G/quest/todotxt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestList(saved *int) (List, *todotxt.List) {
+	return newTestListOf(saved, "", "first task", "second task", "third task")
+}
+
+func listOf(descriptions ...string) *todotxt.List {
+	items := make([]*todotxt.Item, 0, len(descriptions))
+	for _, d := range descriptions {
+		items = append(items, todotxt.MustBuildItem(todotxt.WithDescription(d)))
+	}
+	return todotxt.ListOf(items...)
+}
+
+func newTestListOf(saved *int, idTag string, descriptions ...string) (List, *todotxt.List) {
+	list := listOf(descriptions...)
+	projector := qprojection.Projector{
+		IdTag:      idTag,
+		LineColors: func(*todotxt.List, *todotxt.Item) *lipgloss.Color { return nil },
+	}
+	l := NewList(nil, projector, []string{"line", "description"}, func(l *todotxt.List) []*todotxt.Item { return l.Tasks() }, true)
+	l = l.WithActions(Actions{
+		Save: func(*todotxt.List, string) error {
+			*saved++
+			return nil
+		},
+		Add: func(list *todotxt.List, description string) (*todotxt.Item, error) {
+			item := todotxt.MustBuildItem(todotxt.WithDescription(description))
+			return item, list.Add(item)
+		},
+	})
+	l = l.WithSortCompiler(qsort.Compiler{}.CompileSortFunc)
+	model, _ := l.Update(RefreshListMsg{List: list})
+	return model.(List), list
+}
+
+func press(l List, keys ...string) List {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		model, _ := l.Update(msg)
+		l = model.(List)
+	}
+	return l
+}
+
+func Test_ListCompletesTheTaskUnderTheCursor(t *testing.T) {
+	saved := 0
+	l, list := newTestList(&saved)
+
+	l = press(l, "down", "x")
+
+	assert.False(t, list.GetLine(1).Done())
+	assert.True(t, list.GetLine(2).Done())
+	assert.Equal(t, 1, saved)
+	assert.Contains(t, l.View(), "Toggled 1 task(s)")
+}
+
+func Test_ListAppliesActionsToAllMarkedTasks(t *testing.T) {
+	saved := 0
+	l, list := newTestList(&saved)
+
+	l = press(l, "m", "down", "m", "p", "B", "enter")
+
+	assert.Equal(t, todotxt.PrioB, list.GetLine(1).Priority())
+	assert.Equal(t, todotxt.PrioNone, list.GetLine(2).Priority())
+	assert.Equal(t, todotxt.PrioB, list.GetLine(3).Priority())
+	assert.Empty(t, l.marked)
+}
+
+func Test_ListKeepsMarksOfTasksWithTheSameDescriptionApart(t *testing.T) {
+	saved := 0
+	l, _ := newTestListOf(&saved, "", "same task", "same task", "other task")
+	l = press(l, "down", "m")
+
+	refreshed := listOf("same task", "same task", "other task changed")
+	model, _ := l.Update(RefreshListMsg{List: refreshed})
+
+	assert.Equal(t, map[*todotxt.Item]struct{}{refreshed.GetLine(2): {}}, model.(List).marked)
+}
+
+func Test_ListDropsMarksOfTasksThatChangedWithoutIds(t *testing.T) {
+	saved := 0
+	l, _ := newTestListOf(&saved, "", "first task", "second task")
+	l = press(l, "m")
+
+	model, _ := l.Update(RefreshListMsg{List: listOf("first task due:2022-02-02", "second task")})
+
+	assert.Empty(t, model.(List).marked)
+}
+
+func Test_ListFollowsMarkedTasksByTheirId(t *testing.T) {
+	saved := 0
+	l, _ := newTestListOf(&saved, "id", "first task id:a", "second task id:b")
+	l = press(l, "down", "m")
+
+	refreshed := listOf("new task", "first task id:a", "second task id:b due:2022-02-02")
+	model, _ := l.Update(RefreshListMsg{List: refreshed})
+
+	assert.Equal(t, map[*todotxt.Item]struct{}{refreshed.GetLine(3): {}}, model.(List).marked)
+	assert.Equal(t, refreshed.GetLine(3), model.(List).itemAtCursor())
+}
+
+func Test_ListSetsTagsAndEditsDescriptionsInline(t *testing.T) {
+	saved := 0
+	l, list := newTestList(&saved)
+
+	l = press(l, "s", "due:2022-02-02", "enter")
+	assert.Equal(t, []string{"2022-02-02"}, list.GetLine(1).Tags()["due"])
+
+	l = press(l, "e", " now", "enter")
+	assert.Equal(t, "first task due:2022-02-02 now", list.GetLine(1).Description())
+
+	press(l, "S", "due", "enter")
+	assert.Empty(t, list.GetLine(1).Tags()["due"])
+	assert.Equal(t, 3, saved)
+}
+
+func Test_ListAddsTasks(t *testing.T) {
+	saved := 0
+	l, list := newTestList(&saved)
+
+	l = press(l, "a", "a new task", "enter")
+
+	assert.Equal(t, 4, list.Len())
+	assert.Equal(t, "a new task", list.GetLine(4).Description())
+	assert.Equal(t, list.GetLine(4), l.itemAtCursor())
+}
+
+func Test_ListShowsNotificationsInTheStatusLine(t *testing.T) {
+	saved := 0
+	l, _ := newTestList(&saved)
+
+	model, _ := l.Update(notificationMsg{msg: "Task #2 is no longer blocked"})
+
+	assert.Contains(t, model.View(), "Task #2 is no longer blocked")
+}
+
+func Test_ListIgnoresActionsWithoutSave(t *testing.T) {
+	saved := 0
+	l, list := newTestList(&saved)
+	l.actions = Actions{}
+
+	press(l, "x")
+
+	assert.False(t, list.GetLine(1).Done())
+}
+
+func descriptions(l List) []string {
+	d := make([]string, 0, len(l.selection))
+	for _, i := range l.selection {
+		d = append(d, i.Description())
+	}
+	return d
+}
+
+func Test_ListFiltersWhileTyping(t *testing.T) {
+	saved := 0
+	l, _ := newTestList(&saved)
+
+	l = press(l, "/", "line(it)")
+	assert.Empty(t, l.selection, "incomplete queries search for words")
+
+	l = press(l, " >= 2")
+	assert.Equal(t, []string{"second task", "third task"}, descriptions(l))
+
+	l = press(l, "enter")
+	assert.Nil(t, l.prompt)
+	assert.Equal(t, []string{"second task", "third task"}, descriptions(l))
+}
+
+func Test_ListRestoresTheFilterWhenCancelled(t *testing.T) {
+	saved := 0
+	l, _ := newTestList(&saved)
+
+	l = press(l, "/", "third", "enter")
+	assert.Equal(t, []string{"third task"}, descriptions(l))
+
+	l = press(l, "/", "backspace", "backspace", "backspace", "backspace", "backspace", "first")
+	assert.Equal(t, []string{"first task"}, descriptions(l))
+	l = press(l, "esc")
+	assert.Equal(t, []string{"third task"}, descriptions(l))
+}
+
+func Test_ListChangesSortOrderAndProjection(t *testing.T) {
+	saved := 0
+	l, _ := newTestList(&saved)
+
+	l = press(l, "o", "-description", "enter")
+	assert.Equal(t, []string{"third task", "second task", "first task"}, descriptions(l))
+
+	l = press(l, "o", "+unknown")
+	assert.Contains(t, l.View(), "unknown")
+	l = press(l, "enter")
+	assert.Equal(t, []string{"third task", "second task", "first task"}, descriptions(l))
+	assert.Contains(t, l.View(), "Error:")
+
+	l = press(l, "c", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace")
+	assert.Equal(t, []string{"line"}, l.projection)
+	l = press(l, ",done", "enter")
+	assert.Equal(t, []string{"line", "done"}, l.projection)
+}
+
+func Test_ListAppliesTheLimitAfterTheFilter(t *testing.T) {
+	saved := 0
+	l, _ := newTestList(&saved)
+	l = l.WithLimit(1)
+
+	l = press(l, "/", "second")
+
+	assert.Equal(t, []string{"second task"}, descriptions(l))
+}
@@ -0,0 +1,139 @@
+package view
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Fabian-G/quest/qselect"
+	"github.com/Fabian-G/quest/todotxt"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var hintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+// SortCompiler compiles sort keys (see qsort) into a compare function
+type SortCompiler func(keys []string) (func(*todotxt.Item, *todotxt.Item) int, error)
+
+// liveState is everything that can be changed on the fly from within an interactive list
+type liveState struct {
+	filter     string
+	filterFunc qselect.Func
+	sortKeys   []string
+	sortFunc   func(*todotxt.Item, *todotxt.Item) int
+	projection []string
+}
+
+// WithSortCompiler enables changing the sort order of an interactive list.
+func (l List) WithSortCompiler(compile SortCompiler) List {
+	l.compileSort = compile
+	return l
+}
+
+// WithLimit shows only the first limit tasks. The limit is applied after the live filter.
+func (l List) WithLimit(limit int) List {
+	l.limit = limit
+	return l
+}
+
+func (l List) liveState() liveState {
+	return liveState{
+		filter:     l.filter,
+		filterFunc: l.filterFunc,
+		sortKeys:   l.sortKeys,
+		sortFunc:   l.sortFunc,
+		projection: l.projection,
+	}
+}
+
+func (l List) restore(s liveState) List {
+	l.filter, l.filterFunc = s.filter, s.filterFunc
+	l.sortKeys, l.sortFunc = s.sortKeys, s.sortFunc
+	l.projection = s.projection
+	return l.refreshTable(l.list)
+}
+
+// startLivePrompt opens a prompt whose input is applied while typing
+func (l List) startLivePrompt(kind promptKind) List {
+	var p *prompt
+	switch kind {
+	case promptFilter:
+		p = newPrompt(kind, "Filter", l.filter, nil)
+	case promptSort:
+		p = newPrompt(kind, "Sort", strings.Join(l.sortKeys, ","), nil)
+		p.input.Placeholder = "the sort order of the view"
+	case promptProjection:
+		p = newPrompt(kind, "Columns", strings.Join(l.projection, ","), nil)
+	}
+	p.previous = l.liveState()
+	l.prompt = p
+	return l
+}
+
+// preview applies the current input of a live prompt.
+// Invalid input is reported in the prompt and leaves the list unchanged.
+func (l List) preview() List {
+	p := l.prompt
+	value := strings.TrimSpace(p.input.Value())
+	p.hint = ""
+	switch p.kind {
+	case promptFilter:
+		if value == "" {
+			l.filter, l.filterFunc = "", nil
+			break
+		}
+		filterFunc, err := qselect.CompileQuery(value)
+		if err != nil {
+			p.hint = err.Error()
+			return l
+		}
+		l.filter, l.filterFunc = value, filterFunc
+	case promptSort:
+		keys := splitList(value)
+		if len(keys) == 0 {
+			l.sortKeys, l.sortFunc = nil, nil
+			break
+		}
+		sortFunc, err := l.compileSort(keys)
+		if err != nil {
+			p.hint = err.Error()
+			return l
+		}
+		l.sortKeys, l.sortFunc = keys, sortFunc
+	case promptProjection:
+		projection := splitList(value)
+		if len(projection) == 0 {
+			p.hint = "at least one column is required"
+			return l
+		}
+		if err := l.projector.Verify(projection, l.list); err != nil {
+			p.hint = err.Error()
+			return l
+		}
+		l.projection = projection
+	}
+	return l.refreshTable(l.list)
+}
+
+// applyLiveState filters, sorts and limits the selection according to the live state
+func (l List) applyLiveState(selection []*todotxt.Item) []*todotxt.Item {
+	if l.filterFunc != nil {
+		selection = slices.DeleteFunc(selection, func(i *todotxt.Item) bool { return !l.filterFunc(l.list, i) })
+	}
+	if l.sortFunc != nil {
+		slices.SortStableFunc(selection, l.sortFunc)
+	}
+	if l.limit > 0 {
+		selection = selection[:min(len(selection), l.limit)]
+	}
+	return selection
+}
+
+func splitList(value string) []string {
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
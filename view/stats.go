@@ -0,0 +1,91 @@
+package view
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Fabian-G/quest/qstats"
+	"github.com/Fabian-G/quest/view/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var sectionStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+
+type Statistics struct {
+	report qstats.Report
+}
+
+func NewStatistics(report qstats.Report) Statistics {
+	return Statistics{
+		report: report,
+	}
+}
+
+func (s Statistics) Run() {
+	fmt.Print(s.View())
+}
+
+func (s Statistics) View() string {
+	builder := strings.Builder{}
+	r := s.report
+	writeSection(&builder, "Summary", []string{"Total", "Open", "Done", "Avg. Lead Time"}, [][]string{
+		{strconv.Itoa(r.Total), strconv.Itoa(r.Open), strconv.Itoa(r.Done), formatDays(r.LeadTimeDays)},
+	})
+	timeline := make([][]string, 0, len(r.Timeline))
+	for _, b := range r.Timeline {
+		timeline = append(timeline, []string{b.Label, strconv.Itoa(b.Created), strconv.Itoa(b.Completed)})
+	}
+	writeSection(&builder, "Timeline", []string{"Period", "Created", "Completed"}, timeline)
+	writeGroups(&builder, "Projects", r.Projects)
+	writeGroups(&builder, "Contexts", r.Contexts)
+	tags := make([]string, 0, len(r.Tags))
+	for tag := range r.Tags {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		writeGroups(&builder, fmt.Sprintf("Tag %s", tag), r.Tags[tag])
+	}
+	return builder.String()
+}
+
+func writeGroups(builder *strings.Builder, title string, groups []qstats.Group) {
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, []string{g.Key, strconv.Itoa(g.Open), strconv.Itoa(g.Done), formatDays(g.LeadTimeDays)})
+	}
+	writeSection(builder, title, []string{"Key", "Open", "Done", "Avg. Lead Time"}, rows)
+}
+
+func writeSection(builder *strings.Builder, title string, headings []string, data [][]string) {
+	if len(data) == 0 {
+		return
+	}
+	columns := make([]table.Column, 0, len(headings))
+	for i, h := range headings {
+		width := runewidth.StringWidth(h)
+		for _, row := range data {
+			width = max(width, runewidth.StringWidth(row[i]))
+		}
+		columns = append(columns, table.Column{Title: h, Width: width})
+	}
+	rows := make([]table.Row, 0, len(data))
+	for _, row := range data {
+		rows = append(rows, table.Row(row))
+	}
+	t := table.New(table.WithColumns(columns), table.WithRows(rows), table.WithHeight(len(rows)), table.WithStyles(nonInteractiveStyles))
+	builder.WriteString(sectionStyle.Render(title))
+	builder.WriteString("\n")
+	builder.WriteString(t.View())
+	builder.WriteString("\n\n")
+}
+
+func formatDays(days float64) string {
+	if days == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fd", days)
+}
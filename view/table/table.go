@@ -16,6 +16,7 @@ type Model struct {
 
 	cols   []Column
 	rows   []Row
+	depths []int
 	cursor int
 	focus  bool
 	styles Styles
@@ -35,10 +36,15 @@ type CellPosition struct {
 // Row represents one line in the table.
 type Row []string
 
+// TreeIndent is inserted in front of the tree column once per level of depth.
+const TreeIndent = "  "
+
 // Column defines the table structure.
 type Column struct {
 	Title string
 	Width int
+	// Tree marks the column that is indented according to the depth of the row (see SetDepths).
+	Tree bool
 }
 
 // KeyMap defines keybindings. It satisfies to the help.KeyMap interface, which
@@ -180,6 +186,13 @@ func WithRows(rows []Row) Option {
 	}
 }
 
+// WithDepths sets the depth of each row in a tree.
+func WithDepths(depths []int) Option {
+	return func(m *Model) {
+		m.depths = depths
+	}
+}
+
 // WithHeight sets the height of the table.
 func WithHeight(h int) Option {
 	return func(m *Model) {
@@ -315,6 +328,12 @@ func (m *Model) SetRows(r []Row) {
 	m.UpdateViewport()
 }
 
+// SetDepths sets the depth of each row in a tree. Rows without a depth are not indented.
+func (m *Model) SetDepths(d []int) {
+	m.depths = d
+	m.UpdateViewport()
+}
+
 // SetColumns sets a new columns state.
 func (m *Model) SetColumns(c []Column) {
 	m.cols = c
@@ -427,6 +446,9 @@ func (m *Model) renderRow(rowID int) string {
 
 	s := make([]string, 0, len(m.cols))
 	for i, value := range m.rows[rowID] {
+		if m.cols[i].Tree && rowID < len(m.depths) {
+			value = strings.Repeat(TreeIndent, m.depths[rowID]) + value
+		}
 		style := lipgloss.NewStyle().Width(m.cols[i].Width).MaxWidth(m.cols[i].Width).Inline(true)
 
 		position := CellPosition{
@@ -101,3 +101,23 @@ func TestCellDefault(t *testing.T) {
 		t.Fatalf("Expected: %q in \n%s", expected, rendered)
 	}
 }
+
+func TestTreeColumnIsIndentedByDepth(t *testing.T) {
+	table := New(
+		WithColumns([]Column{{Title: "Line", Width: 4}, {Title: "Description", Width: 20, Tree: true}}),
+		WithRows([]Row{{"1", "parent"}, {"2", "child"}, {"3", "grandchild"}}),
+		WithDepths([]int{0, 1, 2}),
+		WithHeight(3),
+	)
+
+	rendered := table.View()
+
+	for _, expected := range []string{"parent", TreeIndent + "child", TreeIndent + TreeIndent + "grandchild"} {
+		if !strings.Contains(rendered, " "+expected) {
+			t.Fatalf("Expected: %q in \n%s", expected, rendered)
+		}
+	}
+	if strings.Contains(rendered, TreeIndent+"1") {
+		t.Fatalf("Expected only the tree column to be indented in \n%s", rendered)
+	}
+}